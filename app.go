@@ -0,0 +1,203 @@
+package sticky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+// Runnable is a long-running component started by App.
+// Run must return when ctx is canceled.
+type Runnable interface {
+	Run(ctx context.Context) error
+}
+
+// appOption is interface to apply option.
+type appOption interface {
+	applyAppOption(*appOptions)
+}
+
+// appOptions is for the App.
+type appOptions struct {
+	ShutdownTimeout time.Duration
+	Signals         []os.Signal
+}
+
+// ShutdownTimeout option limits the time App waits for the components to stop. default 30 seconds.
+//
+// e.g.
+// - NewApp(c, ShutdownTimeout(10*time.Second))
+func ShutdownTimeout(d time.Duration) *shutdownTimeoutOption {
+	return &shutdownTimeoutOption{d}
+}
+
+type shutdownTimeoutOption struct{ d time.Duration }
+
+func (o *shutdownTimeoutOption) applyAppOption(opt *appOptions) {
+	opt.ShutdownTimeout = o.d
+}
+
+// Signals option replaces the signals that stop App. default SIGINT and SIGTERM.
+//
+// e.g.
+// - NewApp(c, Signals(os.Interrupt))
+func Signals(signals ...os.Signal) *signalsOption {
+	return &signalsOption{signals}
+}
+
+type signalsOption struct{ signals []os.Signal }
+
+func (o *signalsOption) applyAppOption(opt *appOptions) {
+	opt.Signals = o.signals
+}
+
+// App runs an application built from a container.
+type App struct {
+	ctx     stickyContext
+	options appOptions
+}
+
+// NewApp creates an App that runs the dependencies registered in ctx.
+func NewApp(ctx stickyContext, opts ...appOption) *App {
+	options := appOptions{
+		ShutdownTimeout: 30 * time.Second,
+		Signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+	for _, opt := range opts {
+		opt.applyAppOption(&options)
+	}
+	return &App{ctx: ctx, options: options}
+}
+
+type runningComponent struct {
+	key    dKey
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var runnableType = makeType[Runnable]()
+
+var contextType = makeType[context.Context]()
+
+// Run validates the container, starts every registered Runnable concurrently and calls root.
+// arguments of root are resolved from the container, and context.Context arguments receive the context of the App.
+// root must return nothing or an error.
+//
+// Run returns when there are no Runnable and root returns, or after a signal, the cancellation of ctx or
+// the first error. the components are stopped in reverse dependency order, and then the container is closed.
+func (a *App) Run(ctx context.Context, root any) error {
+	c, err := getContainer(a.ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, a.options.Signals...)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var keys []dKey
+	for _, key := range c.keys() {
+		if key.t.Implements(runnableType) {
+			keys = append(keys, key)
+		}
+	}
+	errCh := make(chan error, len(keys)+1)
+	var components []*runningComponent
+	for _, key := range c.dependencyOrder(keys) {
+		v, err := c.Resolve(key)
+		if err != nil {
+			cancel()
+			if sErr := a.shutdown(c, components); sErr != nil {
+				return errors.Join(err, sErr)
+			}
+			return err
+		}
+		// the components are canceled one by one on shutdown, so they do not inherit the cancellation of ctx.
+		runCtx, runCancel := context.WithCancel(context.WithoutCancel(ctx))
+		component := &runningComponent{key: key, cancel: runCancel, done: make(chan struct{})}
+		components = append(components, component)
+		go func(key dKey, r Runnable) {
+			defer close(component.done)
+			if err := r.Run(runCtx); err != nil && runCtx.Err() == nil {
+				errCh <- fmt.Errorf("run %s: %w", key.export(), err)
+			}
+		}(key, v.(Runnable))
+	}
+
+	var runErr error
+	if root != nil {
+		runErr = a.callRoot(ctx, c, root)
+	}
+	if runErr == nil && len(components) > 0 {
+		select {
+		case <-ctx.Done():
+		case runErr = <-errCh:
+		}
+	}
+	cancel()
+	if err := a.shutdown(c, components); err != nil {
+		return errors.Join(runErr, err)
+	}
+	return runErr
+}
+
+// shutdown stops the components in reverse order and closes the container waiting until the shutdown timeout.
+// the container is closed even if a component does not stop in time, waiting for the shutdown timeout again.
+func (a *App) shutdown(c *container, components []*runningComponent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.options.ShutdownTimeout)
+	defer cancel()
+	for i := len(components) - 1; i >= 0; i-- {
+		component := components[i]
+		component.cancel()
+		select {
+		case <-component.done:
+		case <-ctx.Done():
+			for _, rest := range components[:i] {
+				rest.cancel()
+			}
+			// the instances are disposed with a context of their own, since ctx has expired.
+			closeCtx, closeCancel := context.WithTimeout(context.WithoutCancel(ctx), a.options.ShutdownTimeout)
+			defer closeCancel()
+			return errors.Join(&shutdownTimeoutError{component.key}, c.Close(closeCtx))
+		}
+	}
+	return c.Close(ctx)
+}
+
+func (a *App) callRoot(ctx context.Context, c *container, root any) error {
+	fnV := reflect.ValueOf(root)
+	if fnV.Kind() != reflect.Func {
+		return &invalidFunctionError{}
+	}
+	fnT := fnV.Type()
+	if fnT.NumOut() > 1 || (fnT.NumOut() == 1 && fnT.Out(0) != makeType[error]()) {
+		return &invalidFunctionError{}
+	}
+	args := make([]reflect.Value, fnT.NumIn())
+	for i := range args {
+		inT := fnT.In(i)
+		if inT == contextType {
+			args[i] = reflect.ValueOf(ctx)
+			continue
+		}
+		arg, err := c.resolveArg(inT)
+		if err != nil {
+			return err
+		}
+		args[i] = arg
+	}
+	results := fnV.Call(args)
+	if len(results) == 1 && !results[0].IsNil() {
+		return results[0].Interface().(error)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package sticky
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testComponent struct {
+	name    string
+	mu      *sync.Mutex
+	stopped *[]string
+	started chan struct{}
+	err     error
+}
+
+func (c *testComponent) Run(ctx context.Context) error {
+	close(c.started)
+	if c.err != nil {
+		return c.err
+	}
+	<-ctx.Done()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.stopped = append(*c.stopped, c.name)
+	return ctx.Err()
+}
+
+type testDB struct{ *testComponent }
+
+type testServer struct {
+	*testComponent
+	db *testDB
+}
+
+func TestApp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reverse dependency order", func(t *testing.T) {
+		var mu sync.Mutex
+		var stopped []string
+		newComponent := func(name string) *testComponent {
+			return &testComponent{name: name, mu: &mu, stopped: &stopped, started: make(chan struct{})}
+		}
+
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func(db *testDB) *testServer { return &testServer{newComponent("server"), db} }),
+			Constructor(func() *testDB { return &testDB{newComponent("db")} }),
+		))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var called bool
+		err := NewApp(c).Run(ctx, func(ctx context.Context, s *testServer) {
+			<-s.started
+			<-s.db.started
+			called = true
+			cancel()
+		})
+		require.NoError(t, err)
+		assert.True(t, called)
+		assert.Equal(t, []string{"server", "db"}, stopped)
+	})
+
+	t.Run("component error", func(t *testing.T) {
+		var mu sync.Mutex
+		var stopped []string
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() *testDB {
+				return &testDB{&testComponent{mu: &mu, stopped: &stopped, started: make(chan struct{}), err: errors.New("dummy error")}}
+			}),
+		))
+		err := NewApp(c).Run(context.Background(), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dummy error")
+	})
+
+	t.Run("root error", func(t *testing.T) {
+		c := New()
+		err := NewApp(c).Run(context.Background(), func() error {
+			return errors.New("dummy error")
+		})
+		assert.Equal(t, errors.New("dummy error"), err)
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		c := New()
+		block := make(chan struct{})
+		defer close(block)
+		var closed []string
+		require.NoError(t, Register(c,
+			Constructor(func() *testCloser { return &testCloser{name: "closer", closed: &closed} }),
+			Constructor(func() *testShutdowner { return &testShutdowner{} }),
+			Constructor(func(*testCloser, *testShutdowner) *testBlocking { return &testBlocking{block} }),
+		))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := NewApp(c, ShutdownTimeout(10*time.Millisecond)).Run(ctx, nil)
+		var e *shutdownTimeoutError
+		assert.True(t, errors.As(err, &e))
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+		_, err = Resolve[*testBlocking](c)
+		assert.ErrorIs(t, err, ErrContainerClosed, "the container is closed after the shutdown timeout")
+		assert.Equal(t, []string{"closer"}, closed)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		type A struct{}
+		c := New()
+		require.NoError(t, Register(c, Constructor(func(a A) *testDB { return nil })))
+		err := NewApp(c).Run(context.Background(), nil)
+		var e *validationError
+		assert.True(t, errors.As(err, &e))
+	})
+}
+
+type testShutdowner struct{}
+
+func (s *testShutdowner) Shutdown(ctx context.Context) error { return ctx.Err() }
+
+type testBlocking struct{ block chan struct{} }
+
+func (b *testBlocking) Run(context.Context) error {
+	<-b.block
+	return nil
+}
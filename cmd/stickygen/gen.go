@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/printer"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// generate renders the Go source of the graph.
+func generate(g *graph, typeName string) ([]byte, error) {
+	g.nameBindings()
+
+	var body bytes.Buffer
+	w := func(format string, args ...any) {
+		fmt.Fprintf(&body, format, args...)
+	}
+	typ := func(t types.Type) string {
+		return types.TypeString(t, g.qualifier)
+	}
+
+	w("// %s builds the dependencies registered in %s without reflection.\n", typeName, g.fileName)
+	w("type %s struct {\n", typeName)
+	for _, b := range g.bindings {
+		if b.cache {
+			w("v%d %s\n", b.id, typ(b.t))
+			w("ok%d bool\n", b.id)
+		}
+	}
+	w("}\n\n")
+	w("// New%s creates a %s.\n", typeName, typeName)
+	w("func New%s() *%s {\nreturn &%s{}\n}\n", typeName, typeName, typeName)
+
+	for _, b := range g.bindings {
+		w("\n// %s returns %s", b.name, typ(b.t))
+		if b.tag != "" {
+			w(" tagged %s", strconv.Quote(b.tag))
+		}
+		w(".\n")
+		w("func (g *%s) %s() (v %s, err error) {\n", typeName, b.name, typ(b.t))
+		if b.cache {
+			w("if g.ok%d {\nreturn g.v%d, nil\n}\n", b.id, b.id)
+		}
+		lhs := make([]string, len(b.prov.outs))
+		for i := range lhs {
+			lhs[i] = "_"
+		}
+		lhs[b.out] = "v"
+		if b.prov.errIdx < 0 {
+			lhs = append(lhs, "err")
+		} else {
+			lhs[b.prov.errIdx] = "err"
+		}
+		w("%s = g.build%d()\nreturn\n}\n", strings.Join(lhs, ", "), g.providerIndex(b.prov))
+	}
+
+	for i, p := range g.providers {
+		results := make([]string, len(p.outs))
+		lhs := make([]string, len(p.outs))
+		for j, out := range p.outs {
+			if j == p.errIdx {
+				results[j] = "err error"
+				lhs[j] = "err"
+				continue
+			}
+			results[j] = fmt.Sprintf("r%d %s", j, typ(out))
+			lhs[j] = fmt.Sprintf("r%d", j)
+		}
+		if p.errIdx < 0 {
+			results = append(results, "err error")
+		}
+		w("\nfunc (g *%s) build%d() (%s) {\n", typeName, i, strings.Join(results, ", "))
+
+		args := make([]string, len(p.params))
+		for j, pt := range p.params {
+			args[j] = fmt.Sprintf("a%d", j)
+			w("var a%d %s\n", j, typ(pt))
+			w("if a%d, err = g.%s(); err != nil {\nreturn\n}\n", j, g.find(pt, "").name)
+		}
+		switch p.kind {
+		case kindConstructor:
+			w("%s = %s(%s)\n", strings.Join(lhs, ", "), g.exprString(p), strings.Join(args, ", "))
+			if p.errIdx >= 0 {
+				w("if err != nil {\nreturn\n}\n")
+			}
+		case kindParam:
+			w("r0 = %s\n", g.exprString(p))
+		case kindBind:
+			w("r0 = a0\n")
+		}
+		for _, b := range p.bindings {
+			if b != nil && b.cache {
+				w("g.v%d, g.ok%d = r%d, true\n", b.id, b.id, b.out)
+			}
+		}
+		w("return\n}\n")
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by stickygen from %s. DO NOT EDIT.\n\n", g.fileName)
+	fmt.Fprintf(&src, "package %s\n\n", g.pkg.Name())
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		src.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&src, "%s %s\n", g.imports[path], strconv.Quote(path))
+		}
+		src.WriteString(")\n\n")
+	}
+	src.Write(body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// qualifier returns the package name that qualifies the types of other packages and records the import.
+func (g *graph) qualifier(pkg *types.Package) string {
+	if pkg == g.pkg {
+		return ""
+	}
+	if name, ok := g.imports[pkg.Path()]; ok {
+		return name
+	}
+	name := pkg.Name()
+	for i := 2; g.importNameUsed(name); i++ {
+		name = fmt.Sprintf("%s%d", pkg.Name(), i)
+	}
+	g.imports[pkg.Path()] = name
+	return name
+}
+
+func (g *graph) importNameUsed(name string) bool {
+	for _, n := range g.imports {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *graph) exprString(p *provider) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, g.fset, p.expr)
+	return buf.String()
+}
+
+func (g *graph) providerIndex(p *provider) int {
+	for i := range g.providers {
+		if g.providers[i] == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// nameBindings gives every binding a unique accessor name derived from its type and tag.
+func (g *graph) nameBindings() {
+	used := make(map[string]bool)
+	for _, b := range g.bindings {
+		name := typeName(b.t) + exported(b.tag)
+		unique := name
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", name, i)
+		}
+		used[unique] = true
+		b.name = unique
+	}
+}
+
+func typeName(t types.Type) string {
+	for {
+		switch tt := t.(type) {
+		case *types.Pointer:
+			t = tt.Elem()
+			continue
+		case *types.Named:
+			return exported(tt.Obj().Name())
+		case *types.Basic:
+			return exported(tt.Name())
+		case *types.Slice:
+			return typeName(tt.Elem()) + "Slice"
+		case *types.Map:
+			return typeName(tt.Elem()) + "Map"
+		}
+		return "Value"
+	}
+}
+
+// exported converts s to an exported Go identifier. e.g. "memory-service" => "MemoryService"
+func exported(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
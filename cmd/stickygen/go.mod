@@ -0,0 +1,16 @@
+module github.com/ssstoyama/sticky/cmd/stickygen
+
+go 1.22.0
+
+require (
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/tools v0.26.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const stickyPath = "github.com/ssstoyama/sticky"
+
+var errorType = types.Universe.Lookup("error").Type()
+
+type providerKind int
+
+const (
+	kindConstructor providerKind = iota
+	kindParam
+	kindBind
+)
+
+// provider is a registered constructor, parameter or binding.
+type provider struct {
+	pos    token.Pos
+	kind   providerKind
+	expr   ast.Expr
+	params []types.Type
+	outs   []types.Type
+	// errIdx is the index of the error result in outs. -1 if the constructor does not return error.
+	errIdx   int
+	bindings []*binding
+}
+
+// binding is a dependency reachable by its type and tag.
+type binding struct {
+	id    int
+	t     types.Type
+	tag   string
+	cache bool
+	prov  *provider
+	out   int
+	name  string
+}
+
+type graph struct {
+	fset      *token.FileSet
+	file      *ast.File
+	fileName  string
+	pkg       *types.Package
+	info      *types.Info
+	providers []*provider
+	bindings  []*binding
+	imports   map[string]string
+}
+
+type errorList []error
+
+func (l *errorList) add(fset *token.FileSet, pos token.Pos, format string, args ...any) {
+	*l = append(*l, fmt.Errorf("%s: %s", fset.Position(pos), fmt.Sprintf(format, args...)))
+}
+
+// load parses the registrations declared in file.
+func load(file string, cache bool) (*graph, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: filepath.Dir(file),
+	}
+	pkgs, err := packages.Load(cfg, "file="+file)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%s: expected one package, got %d", file, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		var errs []error
+		for _, e := range pkg.Errors {
+			errs = append(errs, e)
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	g := &graph{
+		fset:     pkg.Fset,
+		fileName: filepath.Base(file),
+		pkg:      pkg.Types,
+		info:     pkg.TypesInfo,
+		imports:  make(map[string]string),
+	}
+	for i, f := range pkg.CompiledGoFiles {
+		if f == file {
+			g.file = pkg.Syntax[i]
+		}
+	}
+	if g.file == nil {
+		return nil, fmt.Errorf("%s: not found in package %s", file, pkg.PkgPath)
+	}
+
+	var errs errorList
+	ast.Inspect(g.file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || g.stickyFunc(call) != "Register" {
+			return true
+		}
+		for _, arg := range call.Args[1:] {
+			g.parseRegister(arg, cache, &errs)
+		}
+		return false
+	})
+	if len(errs) == 0 {
+		g.link(&errs)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return g, nil
+}
+
+// stickyFunc returns the name of the sticky function called by call.
+func (g *graph) stickyFunc(call *ast.CallExpr) string {
+	id := g.funcIdent(call)
+	if id == nil {
+		return ""
+	}
+	fn, ok := g.info.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != stickyPath {
+		return ""
+	}
+	return fn.Name()
+}
+
+func (g *graph) funcIdent(call *ast.CallExpr) *ast.Ident {
+	fun := call.Fun
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	}
+	return nil
+}
+
+// typeArg returns i-th type argument of the generic sticky function called by call.
+func (g *graph) typeArg(call *ast.CallExpr, i int) types.Type {
+	inst, ok := g.info.Instances[g.funcIdent(call)]
+	if !ok || inst.TypeArgs.Len() <= i {
+		return nil
+	}
+	return inst.TypeArgs.At(i)
+}
+
+func (g *graph) parseRegister(expr ast.Expr, cache bool, errs *errorList) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		errs.add(g.fset, expr.Pos(), "unsupported registration: must call a sticky register function directly")
+		return
+	}
+
+	p := &provider{pos: call.Pos(), errIdx: -1}
+	var opts []ast.Expr
+	tag := ""
+	switch name := g.stickyFunc(call); name {
+	case "Constructor", "Provide", "Provide1", "Provide2", "Provide3", "Provide4", "Provide5":
+		p.kind = kindConstructor
+		p.expr = call.Args[0]
+		opts = call.Args[1:]
+		sig, ok := g.info.TypeOf(p.expr).Underlying().(*types.Signature)
+		if !ok {
+			errs.add(g.fset, p.expr.Pos(), "invalid constructor. must be factory function. got=%s", g.info.TypeOf(p.expr))
+			return
+		}
+		for i := 0; i < sig.Params().Len(); i++ {
+			p.params = append(p.params, sig.Params().At(i).Type())
+		}
+		for i := 0; i < sig.Results().Len(); i++ {
+			p.outs = append(p.outs, sig.Results().At(i).Type())
+		}
+	case "Param":
+		p.kind = kindParam
+		p.expr = call.Args[0]
+		p.outs = []types.Type{types.Default(g.info.TypeOf(p.expr))}
+		v := g.info.Types[call.Args[1]].Value
+		if v == nil || v.Kind() != constant.String {
+			errs.add(g.fset, call.Args[1].Pos(), "tag must be a constant string")
+			return
+		}
+		tag = constant.StringVal(v)
+	case "Supply":
+		p.kind = kindParam
+		p.expr = call.Args[0]
+		p.outs = []types.Type{g.typeArg(call, 0)}
+		opts = call.Args[1:]
+	case "Bind":
+		p.kind = kindBind
+		it, t := g.typeArg(call, 0), g.typeArg(call, 1)
+		if !types.AssignableTo(t, it) {
+			errs.add(g.fset, call.Pos(), "not implements: type=%s, interface=%s", t, it)
+			return
+		}
+		p.params = []types.Type{t}
+		p.outs = []types.Type{it}
+		opts = call.Args
+	default:
+		errs.add(g.fset, call.Pos(), "unsupported registration: %s", name)
+		return
+	}
+	if p.expr != nil && !g.checkExpr(p.expr, errs) {
+		return
+	}
+
+	var implements types.Type
+	for _, opt := range opts {
+		optCall, ok := opt.(*ast.CallExpr)
+		if !ok {
+			errs.add(g.fset, opt.Pos(), "unsupported option: must call a sticky option function directly")
+			return
+		}
+		switch name := g.stickyFunc(optCall); name {
+		case "Tag":
+			v := g.info.Types[optCall.Args[0]].Value
+			if v == nil || v.Kind() != constant.String {
+				errs.add(g.fset, optCall.Pos(), "tag must be a constant string")
+				return
+			}
+			if tag == "" {
+				tag = constant.StringVal(v)
+			}
+		case "Implements":
+			implements = g.typeArg(optCall, 0)
+		case "Cache":
+			v := g.info.Types[optCall.Args[0]].Value
+			if v == nil || v.Kind() != constant.Bool {
+				errs.add(g.fset, optCall.Pos(), "cache must be a constant bool")
+				return
+			}
+			cache = constant.BoolVal(v)
+		case "Singleton":
+			cache = true
+		case "Transient":
+			cache = false
+		default:
+			errs.add(g.fset, optCall.Pos(), "unsupported option: %s", name)
+			return
+		}
+	}
+
+	p.bindings = make([]*binding, len(p.outs))
+	for i, out := range p.outs {
+		if types.Implements(out, errorType.Underlying().(*types.Interface)) {
+			if !types.Identical(out, errorType) || p.errIdx >= 0 {
+				errs.add(g.fset, p.pos, "unsupported error result %s: constructors may only return one error", out)
+				return
+			}
+			p.errIdx = i
+			continue
+		}
+		b := &binding{
+			t:     out,
+			tag:   tag,
+			cache: cache || p.kind == kindParam,
+			prov:  p,
+			out:   i,
+		}
+		if implements != nil {
+			if !types.AssignableTo(out, implements) {
+				errs.add(g.fset, p.pos, "not implements: type=%s, interface=%s", out, implements)
+				return
+			}
+			b.t = implements
+		}
+		if prev := g.find(b.t, b.tag); prev != nil {
+			errs.add(g.fset, p.pos, "already registered: type=%s, tag=%s (previous registration at %s)",
+				b.t, quoteTag(b.tag), g.fset.Position(prev.prov.pos))
+			return
+		}
+		b.id = len(g.bindings)
+		p.bindings[i] = b
+		g.bindings = append(g.bindings, b)
+	}
+	g.providers = append(g.providers, p)
+}
+
+// checkExpr makes sure that expr can be copied to the generated file.
+func (g *graph) checkExpr(expr ast.Expr, errs *errorList) bool {
+	ok := true
+	ast.Inspect(expr, func(n ast.Node) bool {
+		id, isIdent := n.(*ast.Ident)
+		if !isIdent {
+			return true
+		}
+		obj := g.info.Uses[id]
+		if obj == nil || obj.Pkg() == nil || obj.Parent() == nil {
+			return true
+		}
+		if pn, isPkg := obj.(*types.PkgName); isPkg {
+			g.imports[pn.Imported().Path()] = pn.Name()
+			return true
+		}
+		if obj.Pkg() != g.pkg || obj.Parent() == g.pkg.Scope() || (obj.Pos() >= expr.Pos() && obj.Pos() < expr.End()) {
+			return true
+		}
+		errs.add(g.fset, id.Pos(), "%s is not declared at package level: registrations must only refer package-level identifiers", id.Name)
+		ok = false
+		return true
+	})
+	return ok
+}
+
+func (g *graph) find(t types.Type, tag string) *binding {
+	for _, b := range g.bindings {
+		if b.tag == tag && types.Identical(b.t, t) {
+			return b
+		}
+	}
+	return nil
+}
+
+// link makes sure that every argument of the providers is registered and that there is no cycle.
+func (g *graph) link(errs *errorList) {
+	for _, p := range g.providers {
+		for _, pt := range p.params {
+			if g.find(pt, "") == nil {
+				errs.add(g.fset, p.pos, "not found register: type=%s, tag=''", pt)
+			}
+		}
+	}
+	if len(*errs) > 0 {
+		return
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*provider]int)
+	var path []types.Type
+	var visit func(p *provider) bool
+	visit = func(p *provider) bool {
+		switch state[p] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+		state[p] = visiting
+		for _, pt := range p.params {
+			path = append(path, pt)
+			if !visit(g.find(pt, "").prov) {
+				return false
+			}
+			path = path[:len(path)-1]
+		}
+		state[p] = visited
+		return true
+	}
+	for _, p := range g.providers {
+		path = path[:0]
+		if !visit(p) {
+			errs.add(g.fset, p.pos, "cycle dependency: %s", typesString(path))
+			return
+		}
+	}
+}
+
+func typesString(ts []types.Type) string {
+	s := ""
+	for i, t := range ts {
+		if i > 0 {
+			s += " -> "
+		}
+		s += t.String()
+	}
+	return s
+}
+
+func quoteTag(tag string) string {
+	if tag == "" {
+		return `''`
+	}
+	return strconv.Quote(tag)
+}
@@ -0,0 +1,62 @@
+// Command stickygen generates reflection-free wiring from sticky registrations.
+//
+// stickygen reads the sticky.Register calls of a Go file and writes a Graph type
+// that builds the registered dependencies with direct function calls.
+// missing and cyclic dependencies are reported as errors at generation time.
+//
+// usage:
+//
+//	//go:generate go run github.com/ssstoyama/sticky/cmd/stickygen -file wire.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.File, "file", "", "Go file declaring the registrations (required)")
+	flag.StringVar(&cfg.Out, "out", "", "output file (default <file>_sticky.go)")
+	flag.StringVar(&cfg.TypeName, "type", "Graph", "name of the generated type")
+	flag.BoolVar(&cfg.Cache, "cache", true, "cache option of the container the registrations are for")
+	flag.Parse()
+
+	if cfg.File == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if cfg.Out == "" {
+		cfg.Out = strings.TrimSuffix(cfg.File, ".go") + "_sticky.go"
+	}
+	if err := run(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+type config struct {
+	File     string
+	Out      string
+	TypeName string
+	Cache    bool
+}
+
+func run(cfg config) error {
+	file, err := filepath.Abs(cfg.File)
+	if err != nil {
+		return err
+	}
+	g, err := load(file, cfg.Cache)
+	if err != nil {
+		return err
+	}
+	src, err := generate(g, cfg.TypeName)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cfg.Out, src, 0o644)
+}
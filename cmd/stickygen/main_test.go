@@ -0,0 +1,70 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGenerate(t *testing.T) {
+	file, err := filepath.Abs("testdata/wire/wire.go")
+	require.NoError(t, err)
+	g, err := load(file, true)
+	require.NoError(t, err)
+	src, err := generate(g, "Graph")
+	require.NoError(t, err)
+
+	out := string(src)
+	assert.Contains(t, out, "// Code generated by stickygen from wire.go. DO NOT EDIT.")
+	assert.Contains(t, out, "func (g *Graph) ServiceMain() (v *Service, err error) {")
+	assert.Contains(t, out, "func (g *Graph) IntPort() (v int, err error) {")
+	assert.Contains(t, out, "r0, err = NewClient(a0)")
+	assert.NotContains(t, out, "v2 ", "Cache(false) binding must not be stored")
+
+	// the generated file must compile together with the registrations.
+	genFile := filepath.Join(filepath.Dir(file), "wire_sticky.go")
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps,
+		Dir:     filepath.Dir(file),
+		Overlay: map[string][]byte{genFile: src},
+	}, ".")
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	assert.Empty(t, pkgs[0].Errors)
+}
+
+func TestGenerateFailure(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		error string
+	}{
+		{
+			name:  "missing dependency",
+			input: "testdata/missing/missing.go",
+			error: "missing.go:12:28: not found register: type=*github.com/ssstoyama/sticky/cmd/stickygen/testdata/missing.A, tag=''",
+		},
+		{
+			name:  "cycle dependency",
+			input: "testdata/cycle/cycle.go",
+			error: "cycle dependency",
+		},
+		{
+			name:  "local variable",
+			input: "testdata/local/local.go",
+			error: "endpoint is not declared at package level",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := filepath.Abs(tt.input)
+			require.NoError(t, err)
+			_, err = load(file, true)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.error)
+		})
+	}
+}
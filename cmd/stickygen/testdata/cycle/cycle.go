@@ -0,0 +1,15 @@
+package cycle
+
+import "github.com/ssstoyama/sticky"
+
+type A struct{}
+
+type B struct{}
+
+func NewA(b B) A { return A{} }
+
+func NewB(a A) B { return B{} }
+
+func Register(c sticky.Container) error {
+	return sticky.Register(c, sticky.Constructor(NewA), sticky.Constructor(NewB))
+}
@@ -0,0 +1,10 @@
+module github.com/ssstoyama/sticky/cmd/stickygen/testdata
+
+go 1.21
+
+require github.com/ssstoyama/sticky v0.0.0-00010101000000-000000000000
+
+require gopkg.in/yaml.v3 v3.0.1 // indirect
+
+// the test data registers dependencies with the sticky of this repository.
+replace github.com/ssstoyama/sticky => ../../..
@@ -0,0 +1,7 @@
+package local
+
+import "github.com/ssstoyama/sticky"
+
+func Register(c sticky.Container, endpoint string) error {
+	return sticky.Register(c, sticky.Param(endpoint, "endpoint"))
+}
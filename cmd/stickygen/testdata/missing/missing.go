@@ -0,0 +1,13 @@
+package missing
+
+import "github.com/ssstoyama/sticky"
+
+type A struct{}
+
+type B struct{}
+
+func NewB(a *A) *B { return &B{} }
+
+func Register(c sticky.Container) error {
+	return sticky.Register(c, sticky.Constructor(NewB))
+}
@@ -0,0 +1,41 @@
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/ssstoyama/sticky"
+)
+
+type Config struct{ Endpoint string }
+
+type Client struct{ Config *Config }
+
+type Service struct {
+	Client *Client
+	Writer io.Writer
+}
+
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("empty endpoint")
+	}
+	return &Client{cfg}, nil
+}
+
+func NewService(c *Client, w io.Writer) *Service {
+	return &Service{c, w}
+}
+
+func Register(c sticky.Container) error {
+	return sticky.Register(c,
+		sticky.Supply(&Config{Endpoint: "localhost"}),
+		sticky.Constructor(NewClient),
+		sticky.Constructor(bytes.NewBufferString, sticky.Cache(false)),
+		sticky.Supply("hello"),
+		sticky.Param(8080, "port"),
+		sticky.Bind[io.Writer, *bytes.Buffer](),
+		sticky.Constructor(NewService, sticky.Tag("main")),
+	)
+}
@@ -3,35 +3,108 @@ package sticky
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"reflect"
+	"sort"
+	"sync"
+	"time"
 )
 
-// Container is DI container
+// Container is DI container.
+// it is also a context.Context that delegates to the context given by BaseContext option.
 type Container interface {
-	stickyContext
+	context.Context
 	WithContext(ctx context.Context) context.Context
+	Scope() Container
+	Clone() Container
+	Snapshot() Container
+	Close(ctx context.Context) error
+	Health(ctx context.Context) HealthReport
+	Instances() []Instance
 }
 
 func newContainer(opts ...containerOption) *container {
 	c := &container{
 		dependencies: make(map[dKey]*dependency),
+		decorators:   make(map[reflect.Type][]*decorator),
+		interceptors: make(map[reflect.Type][]*interceptor),
+		scoped:       make(map[dKey]any),
+		pools:        make(map[dKey]*pool),
+		locals:       make(map[dKey]map[uint64]any),
+		inflight:     make(map[dKey]*flight),
 		cache:        true,
 		invoker:      defaultInvoker,
+		observer:     nopObserver{},
 	}
 	option := containerOptions{
-		Cache: true,
+		Cache:         true,
+		HealthTimeout: 5 * time.Second,
 	}
 	for _, opt := range opts {
 		opt.applyContainerOption(&option)
 	}
 	c.cache = option.Cache
+	c.healthTimeout = option.HealthTimeout
+	c.observer = newObserver(option.Observers)
+	c.ctx = option.Context
+	if option.Parent != nil {
+		if p, err := getContainer(option.Parent); err == nil {
+			c.parent = p
+			if c.ctx == nil {
+				c.ctx = p
+			}
+		}
+	}
 	return c
 }
 
 type container struct {
+	// mu guards the registrations and the instances. it is never held while a constructor is called.
+	mu           sync.Mutex
 	dependencies map[dKey]*dependency
+	decorators   map[reflect.Type][]*decorator
+	interceptors map[reflect.Type][]*interceptor
+	generics     []*generic
 	cache        bool
 	invoker      invoker
+	observer     Observer
+	parent       *container
+	ctx          context.Context
+	// healthTimeout limits the time each health check takes.
+	healthTimeout time.Duration
+	// scoped, pools and locals keep the instances of the scoped, pooled and thread local dependencies.
+	scoped map[dKey]any
+	pools  map[dKey]*pool
+	locals map[dKey]map[uint64]any
+	// dryRun is true for the container that validates without calling the constructors.
+	dryRun bool
+	// inflight is the singleton and scoped dependencies being built.
+	inflight map[dKey]*flight
+	// built is the cached instances in creation order.
+	built  []instance
+	closed bool
+}
+
+// instance is a cached instance of a dependency.
+type instance struct {
+	key    dKey
+	value  any
+	dep    *dependency
+	record *buildRecord
+	// gid is the goroutine of a thread local instance.
+	gid uint64
+	// inherited is true for the instances copied by Snapshot, which are disposed by the original container.
+	inherited bool
+}
+
+// flight is a build of a dependency that the other goroutines wait for.
+type flight struct {
+	done  chan struct{}
+	value any
+	err   error
+	// gid is the goroutine that builds the dependency.
+	gid uint64
 }
 
 // WithContext saves the container in the context and returns it.
@@ -40,26 +113,228 @@ func (c *container) WithContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, defaultKey, c)
 }
 
-// Value is a method to be implemented to satisfy the stickyContext interface.
+// Scope creates a child container.
+// the child resolves the dependencies that are not registered in it from c, and its registrations shadow the ones of c.
+// dependencies resolved from c are built and cached in c.
+func (c *container) Scope() Container {
+	child := newContainer(Parent(c), Cache(c.cache), HealthTimeout(c.healthTimeout))
+	child.observer = c.observer
+	return child
+}
+
+// Clone creates an independent container with the same registrations. built instances are discarded,
+// including the ones edited by Decorate.
+func (c *container) Clone() Container {
+	return c.clone(false)
+}
+
+// Snapshot creates an independent container with the same registrations and built instances.
+// the built instances are still owned by c, so closing the snapshot disposes only the instances it built itself.
+func (c *container) Snapshot() Container {
+	return c.clone(true)
+}
+
+func (c *container) clone(keepInstances bool) *container {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_c := &container{
+		cache:         c.cache,
+		invoker:       c.invoker,
+		observer:      c.observer,
+		parent:        c.parent,
+		ctx:           c.ctx,
+		inflight:      make(map[dKey]*flight),
+		healthTimeout: c.healthTimeout,
+		closed:        c.closed,
+	}
+	_c.dependencies = make(map[dKey]*dependency, len(c.dependencies))
+	for key, dep := range c.dependencies {
+		d := *dep
+		if !keepInstances && !d.isParam {
+			d.instance = nil
+		}
+		_c.dependencies[key] = &d
+	}
+	_c.built = nil
+	_c.scoped = make(map[dKey]any)
+	_c.pools = make(map[dKey]*pool)
+	_c.locals = make(map[dKey]map[uint64]any)
+	if keepInstances {
+		_c.built = make([]instance, len(c.built))
+		for i, inst := range c.built {
+			inst.inherited = true
+			_c.built[i] = inst
+		}
+		for key, v := range c.scoped {
+			_c.scoped[key] = v
+		}
+		for key, p := range c.pools {
+			_c.pools[key] = p.clone()
+		}
+		for key, locals := range c.locals {
+			_c.locals[key] = make(map[uint64]any, len(locals))
+			for id, v := range locals {
+				_c.locals[key][id] = v
+			}
+		}
+	}
+	_c.decorators = make(map[reflect.Type][]*decorator, len(c.decorators))
+	for t, decorators := range c.decorators {
+		_c.decorators[t] = append([]*decorator(nil), decorators...)
+	}
+	// the generics are copied, since they record the keys instantiated in the container.
+	_c.generics = make([]*generic, len(c.generics))
+	for i, g := range c.generics {
+		_g := *g
+		_g.keys = append([]dKey(nil), g.keys...)
+		_c.generics[i] = &_g
+	}
+	_c.interceptors = make(map[reflect.Type][]*interceptor, len(c.interceptors))
+	for t, interceptors := range c.interceptors {
+		_c.interceptors[t] = append([]*interceptor(nil), interceptors...)
+	}
+	return _c
+}
+
+// Value returns the container for the key used by WithContext,
+// and delegates the other keys to the base context.
 func (c *container) Value(key any) any {
-	return errors.New("not implemented")
+	if key == defaultKey {
+		return c
+	}
+	if c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Value(key)
+}
+
+// Deadline delegates to the base context.
+func (c *container) Deadline() (time.Time, bool) {
+	if c.ctx == nil {
+		return time.Time{}, false
+	}
+	return c.ctx.Deadline()
+}
+
+// Done delegates to the base context.
+func (c *container) Done() <-chan struct{} {
+	if c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Done()
+}
+
+// Err delegates to the base context.
+func (c *container) Err() error {
+	if c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Err()
 }
 
 // Register registers a dependency.
-func (c *container) Register(rter register) error {
+func (c *container) Register(rter Registration) error {
+	_, err := c.register(rter, false)
+	return err
+}
+
+// Override registers a dependency replacing the registered one with the same key,
+// and disposes the cached instances. it returns the function that restores the replaced registrations
+// and disposes the instances built with the replacement.
+// if the cached instances can not be disposed, the replaced registrations are restored.
+func (c *container) Override(rter Registration) (func() error, error) {
+	unregister, err := c.override(rter)
+	if err != nil {
+		return nil, err
+	}
+	restore := func() error {
+		c.mu.Lock()
+		unregister()
+		built := c.discard()
+		c.mu.Unlock()
+		return disposeAll(context.Background(), built)
+	}
+	c.mu.Lock()
+	built := c.discard()
+	c.mu.Unlock()
+	if err := disposeAll(context.Background(), built); err != nil {
+		return nil, errors.Join(err, restore())
+	}
+	return restore, nil
+}
+
+// override registers rter replacing the registrations with the same keys.
+// it returns the function that unregisters rter and restores the replaced registrations. c.mu must be held to call it.
+func (c *container) override(rter Registration) (func(), error) {
+	switch r := rter.(type) {
+	case *decoratorRegister:
+		d, err := c.registerDecorator(r)
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			c.decorators[d.t] = without(c.decorators[d.t], d)
+		}, nil
+	case *interceptorRegister:
+		i, err := c.registerInterceptor(r)
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			c.interceptors[r.t] = without(c.interceptors[r.t], i)
+		}, nil
+	case *genericRegister:
+		g := c.registerGeneric(r)
+		return func() {
+			c.generics = without(c.generics, g)
+			for _, key := range g.keys {
+				delete(c.dependencies, key)
+			}
+		}, nil
+	}
+	replaced, err := c.register(rter, true)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		for key, dep := range replaced {
+			if dep == nil {
+				delete(c.dependencies, key)
+				continue
+			}
+			c.dependencies[key] = dep
+		}
+	}, nil
+}
+
+// register registers a dependency. if override is true, it returns the replaced dependencies,
+// whose values are nil for the keys that were not registered.
+func (c *container) register(rter Registration, override bool) (map[dKey]*dependency, error) {
+	switch r := rter.(type) {
+	case *decoratorRegister:
+		_, err := c.registerDecorator(r)
+		return nil, err
+	case *interceptorRegister:
+		_, err := c.registerInterceptor(r)
+		return nil, err
+	case *genericRegister:
+		c.registerGeneric(r)
+		return nil, nil
+	}
 	keys, err := rter.Keys()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	deps, err := rter.Deps()
 	if err != nil {
-		return err
-	}
-	if err := assertNotCycle(c, deps); err != nil {
-		return err
+		return nil, err
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	opts := rter.Opts()
 
+	regKeys := make([]dKey, 0, len(keys))
+	regDeps := make([]*dependency, 0, len(deps))
 	for i := range keys {
 		key := keys[i]
 		dep := deps[i]
@@ -73,44 +348,259 @@ func (c *container) Register(rter register) error {
 			opt.applyRegisterOption(&options)
 		}
 		if err := c.applyRegisterOption(&key, dep, &options); err != nil {
-			return err
+			return nil, err
 		}
 
 		if !dep.isParam {
 			if err := assertConstructor(dep.value); err != nil {
-				return err
+				return nil, err
 			}
 		}
+		if _, ok := c.dependencies[key]; ok && !override {
+			return nil, &alreadyRegisteredError{key}
+		}
+		regKeys = append(regKeys, key)
+		regDeps = append(regDeps, dep)
+	}
+	if err := assertNotCycle(c, regKeys, regDeps); err != nil {
+		return nil, err
+	}
 
-		if _, ok := c.dependencies[key]; ok {
-			return &alreadyRegisteredError{key}
+	replaced := make(map[dKey]*dependency)
+	for i, key := range regKeys {
+		dep := regDeps[i]
+		dep.order = len(c.dependencies)
+		if prev, ok := c.dependencies[key]; ok {
+			dep.order = prev.order
 		}
+		replaced[key] = c.dependencies[key]
 		c.dependencies[key] = dep
+		c.observer.OnRegister(key.export())
 	}
-	return nil
+	return replaced, nil
+}
+
+// registerAll registers the dependencies of rters. if one of them can not be registered,
+// the ones registered before it are removed, and its index is returned with the error.
+func (c *container) registerAll(rters []Registration) (int, error) {
+	var registered []dKey
+	for i, rter := range rters {
+		replaced, err := c.register(rter, false)
+		if err != nil {
+			c.mu.Lock()
+			for _, key := range registered {
+				delete(c.dependencies, key)
+			}
+			c.mu.Unlock()
+			return i, err
+		}
+		for key := range replaced {
+			registered = append(registered, key)
+		}
+	}
+	return 0, nil
+}
+
+// discard discards the cached instances and returns the built ones to be disposed. c.mu must be held.
+func (c *container) discard() []instance {
+	for _, dep := range c.dependencies {
+		if !dep.isParam {
+			dep.instance = nil
+		}
+	}
+	built := c.built
+	c.built = nil
+	c.scoped = make(map[dKey]any)
+	c.pools = make(map[dKey]*pool)
+	c.locals = make(map[dKey]map[uint64]any)
+	return built
+}
+
+// Built reports whether the dependency has a cached instance.
+// for the scoped dependencies, it reports whether the instance of c is built.
+func (c *container) Built(key dKey) (bool, error) {
+	dep, owner := c.lookup(key)
+	if dep == nil {
+		return false, &notFoundRegisterError{key}
+	}
+	lt := owner.lifetimeOf(dep)
+	if lt == lifetimeScoped {
+		owner = c
+	}
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+	switch lt {
+	case lifetimeScoped:
+		_, ok := c.scoped[key]
+		return ok, nil
+	case lifetimePooled:
+		p, ok := owner.pools[key]
+		return ok && p.created > 0, nil
+	case lifetimeThreadLocal:
+		_, ok := owner.locals[key][goroutineID()]
+		return ok, nil
+	}
+	_, ok := dep.getValue()
+	return ok, nil
+}
+
+// lookup finds the dependency of key in c and its parents, and returns it with the container that registers it.
+func (c *container) lookup(key dKey) (*dependency, *container) {
+	for cc := c; cc != nil; cc = cc.parent {
+		if dep, ok := cc.local(key); ok {
+			return dep, cc
+		}
+	}
+	return nil, nil
+}
+
+// local returns the dependency of key registered in c.
+func (c *container) local(key dKey) (*dependency, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dep, ok := c.dependencies[key]
+	return dep, ok
+}
+
+// lifetimeOf returns the lifetime of dep registered in c.
+func (c *container) lifetimeOf(dep *dependency) lifetime {
+	if dep.isParam {
+		return lifetimeSingleton
+	}
+	if dep.lifetime != lifetimeDefault {
+		return dep.lifetime
+	}
+	if c.cache {
+		return lifetimeSingleton
+	}
+	return lifetimeTransient
 }
 
 // Resolve resolves a dependency.
 func (c *container) Resolve(key dKey) (any, error) {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return nil, ErrContainerClosed
+	}
+	if dep, _ := c.lookup(key); dep == nil {
+		if key.t.Implements(namedParamType) {
+			return c.resolveNamed(key.t)
+		}
+		ok, err := c.instantiate(key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, &notFoundRegisterError{key}
+		}
+	}
+	if _, ok := c.local(key); !ok && c.parent != nil {
+		// scoped dependencies are built in the scope that resolves them.
+		if dep, owner := c.parent.lookup(key); dep != nil && owner.lifetimeOf(dep) == lifetimeScoped {
+			return c.resolve(key, dep, lifetimeScoped)
+		}
+		return c.parent.Resolve(key)
+	}
 	dep, err := c.findDep(key)
 	if err != nil {
 		return nil, err
 	}
 	if dep.isParam {
-		return dep.value.Interface(), nil
+		// the decorators of a param are applied each time it is resolved.
+		values := []any{dep.value.Interface()}
+		if err := c.decorate(key, values); err != nil {
+			return nil, err
+		}
+		return values[0], nil
+	}
+	return c.resolve(key, dep, c.lifetimeOf(dep))
+}
+
+// resolve returns the cached instance of key or builds it.
+// concurrent resolutions of a singleton or scoped dependency wait for the first one to build it.
+// a panic of the constructor is returned as an error.
+func (c *container) resolve(key dKey, dep *dependency, lt lifetime) (v any, err error) {
+	var (
+		ok bool
+		f  *flight
+	)
+	c.mu.Lock()
+	switch lt {
+	case lifetimeSingleton:
+		v, ok = dep.getValue()
+	case lifetimeScoped:
+		v, ok = c.scoped[key]
+	case lifetimePooled:
+		p := c.pool(key, dep)
+		if v, ok = p.get(); !ok {
+			if p.full() {
+				c.mu.Unlock()
+				return nil, &poolExhaustedError{key, p.size}
+			}
+			// reserve the instance being built.
+			p.created++
+		}
+	case lifetimeThreadLocal:
+		v, ok = c.locals[key][goroutineID()]
+	}
+	if !ok && (lt == lifetimeSingleton || lt == lifetimeScoped) {
+		if running, ok := c.inflight[key]; ok {
+			c.mu.Unlock()
+			// the goroutine building the dependency depends on it.
+			if running.gid == goroutineID() {
+				return nil, &cycleDependencyError{[]reflect.Type{key.t}}
+			}
+			<-running.done
+			return running.value, running.err
+		}
+		f = &flight{done: make(chan struct{}), gid: goroutineID()}
+		c.inflight[key] = f
 	}
-	if v, ok := dep.getValue(); ok {
+	c.mu.Unlock()
+	if ok {
+		c.observer.OnCacheHit(key.export())
 		return v, nil
 	}
-	values, err := c.call(dep.value)
+
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = nil, &constructorPanicError{key, r}
+		}
+		if f != nil {
+			f.value, f.err = v, err
+			c.mu.Lock()
+			delete(c.inflight, key)
+			c.mu.Unlock()
+			close(f.done)
+		}
+		if err != nil && lt == lifetimePooled {
+			c.mu.Lock()
+			c.pools[key].created--
+			c.mu.Unlock()
+		}
+	}()
+	return c.build(key, dep)
+}
+
+// build calls the constructor of key and stores the results.
+func (c *container) build(key dKey, dep *dependency) (any, error) {
+	start := time.Now()
+	values, args, err := c.call(key, dep)
 	if err != nil {
 		return nil, err
 	}
-	result, err := c.pick(key.t, values)
-	if err != nil {
+	if _, err := c.pick(key.t, values); err != nil {
 		return nil, err
 	}
-	if err := c.commit(key, values); err != nil {
+	if err := c.decorate(key, values); err != nil {
+		return nil, err
+	}
+	c.intercept(key, values)
+	result, _ := c.pick(key.t, values)
+	b := &buildRecord{fn: dep.value, at: start, duration: time.Since(start), wires: c.wiring(dep, args)}
+	if err := c.commit(key, values, b); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -123,18 +613,12 @@ func (c *container) Extract(function any) error {
 		return &invalidFunctionError{}
 	}
 
-	fnT := fnV.Type()
-	args := make([]reflect.Value, fnT.NumIn())
-	for i := range args {
-		inT := fnT.In(i)
-		arg, err := c.Resolve(dKey{t: inT})
-		if err != nil {
-			return err
-		}
-		args[i] = reflect.ValueOf(arg)
+	args, err := c.resolveArgs(fnV.Type(), "")
+	if err != nil {
+		return err
 	}
-	fnV.Call(args)
-	return nil
+	_, err = defaultInvoker(fnV, args)
+	return err
 }
 
 // Decorate allows to edit instance of generated dependencies.
@@ -151,24 +635,224 @@ func (c *container) Decorate(key dKey, function func(any) (any, error)) error {
 	if err != nil {
 		return err
 	}
+	c.mu.Lock()
 	dep.instance = decorated
-	var cached = true
-	dep.cache = &cached
+	dep.lifetime = lifetimeSingleton
+	c.mu.Unlock()
+	c.observer.OnDecorate(key.export())
+	return nil
+}
+
+// registerGeneric registers a factory of the constructors of a generic type.
+func (c *container) registerGeneric(gr *genericRegister) *generic {
+	var options registerOptions
+	for _, opt := range gr.opts {
+		opt.applyRegisterOption(&options)
+	}
+	g := &generic{factory: gr.factory, tag: options.Tag, opts: gr.opts}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generics = append(c.generics, g)
+	return g
+}
+
+// genericFactories returns the generic factories of c.
+func (c *container) genericFactories() []*generic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*generic(nil), c.generics...)
+}
+
+// instantiate registers the constructor of key built by the generic factories of c and its parents.
+// the constructor is registered in the container of the factory. it returns false if no factory builds key.
+func (c *container) instantiate(key dKey) (bool, error) {
+	for cc := c; cc != nil; cc = cc.parent {
+		for _, g := range cc.genericFactories() {
+			if g.tag != key.tag {
+				continue
+			}
+			fn := g.factory(key.t)
+			if fn == nil {
+				continue
+			}
+			registered, err := cc.register(Constructor(fn, g.opts...), false)
+			if err != nil {
+				// another goroutine may have instantiated key.
+				var aErr *alreadyRegisteredError
+				if errors.As(err, &aErr) {
+					if dep, _ := cc.lookup(key); dep != nil {
+						return true, nil
+					}
+				}
+				return false, err
+			}
+			cc.mu.Lock()
+			for k := range registered {
+				g.keys = append(g.keys, k)
+			}
+			cc.mu.Unlock()
+			if dep, _ := cc.lookup(key); dep == nil {
+				return false, &invalidGenericError{key, reflect.TypeOf(fn)}
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// registerDecorator registers a decorator applied each time the decorated dependency is built.
+func (c *container) registerDecorator(dr *decoratorRegister) (*decorator, error) {
+	d, err := dr.decorator()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.decorators[d.t] = append(c.decorators[d.t], d)
+	return d, nil
+}
+
+// decoratorsOf returns the decorators of t.
+func (c *container) decoratorsOf(t reflect.Type) []*decorator {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*decorator(nil), c.decorators[t]...)
+}
+
+// decorate applies the registered decorators to the values built for sKey in registration order.
+// the decorators are the ones of the container that registers the dependency.
+func (c *container) decorate(sKey dKey, values []any) error {
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		if _, ok := value.(error); ok {
+			continue
+		}
+		key := c.valueKey(sKey, value)
+		_, owner := c.lookup(key)
+		if owner == nil {
+			owner = c
+		}
+		for _, d := range owner.decoratorsOf(key.t) {
+			if d.tag != "" && d.tag != key.tag {
+				continue
+			}
+			decorated, err := c.callDecorator(d, value)
+			if err != nil {
+				return err
+			}
+			value = decorated
+			c.observer.OnDecorate(key.export())
+		}
+		values[i] = value
+	}
 	return nil
 }
 
+// callDecorator resolves the arguments of the decorator and executes it with value.
+func (c *container) callDecorator(d *decorator, value any) (any, error) {
+	fnT := d.value.Type()
+	args := make([]reflect.Value, fnT.NumIn())
+	args[0] = reflect.ValueOf(value)
+	for i := 1; i < len(args); i++ {
+		arg, err := c.resolveArg(fnT.In(i))
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	results, err := c.invoker(d.value, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > 1 && !results[1].IsNil() {
+		return nil, results[1].Interface().(error)
+	}
+	return results[0].Interface(), nil
+}
+
+// registerInterceptor registers an interceptor keeping the interceptors of the same interface ordered by priority.
+func (c *container) registerInterceptor(ir *interceptorRegister) (*interceptor, error) {
+	if _, err := ir.Keys(); err != nil {
+		return nil, err
+	}
+	options := registerOptions{}
+	for _, opt := range ir.opts {
+		opt.applyRegisterOption(&options)
+	}
+	i := &interceptor{
+		fn:       ir.fn,
+		tag:      options.Tag,
+		priority: options.Priority,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	interceptors := append(c.interceptors[ir.t], i)
+	sort.SliceStable(interceptors, func(a, b int) bool {
+		return interceptors[a].priority < interceptors[b].priority
+	})
+	c.interceptors[ir.t] = interceptors
+	return i, nil
+}
+
+// interceptorsOf returns the interceptors of t ordered by priority.
+func (c *container) interceptorsOf(t reflect.Type) []*interceptor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*interceptor(nil), c.interceptors[t]...)
+}
+
+// intercept wraps the values built for the bindings registered with Implements option.
+// interceptors with higher priority wrap the others. the interceptors are the ones of the container that registers the dependency.
+func (c *container) intercept(sKey dKey, values []any) {
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		key := c.valueKey(sKey, value)
+		dep, owner := c.lookup(key)
+		if dep == nil || dep.implements == nil || *dep.implements != key.t {
+			continue
+		}
+		interceptors := owner.interceptorsOf(key.t)
+		if len(interceptors) == 0 {
+			continue
+		}
+		for _, ic := range interceptors {
+			if ic.tag != "" && ic.tag != key.tag {
+				continue
+			}
+			value = ic.fn(value)
+		}
+		values[i] = value
+	}
+}
+
 // Validate verifies that the dependencies are registered without omission.
 func (c *container) Validate() error {
-	_c := *c
-	_c.cache = false
-	_c.invoker = dryInvoker
+	_c := c.dry()
 	var vErr validationError
-	for _, dep := range c.dependencies {
+	for _, key := range c.keys() {
+		dep, _ := c.local(key)
 		if dep.isParam {
 			continue
 		}
-		fn := dep.value
-		if _, err := _c.call(fn); err != nil {
+		if _, _, err := _c.call(key, dep); err != nil {
+			vErr.errs = append(vErr.errs, err)
+		}
+		if err := c.assertNotCaptive(key, dep); err != nil {
+			vErr.errs = append(vErr.errs, err)
+		}
+	}
+	c.mu.Lock()
+	decorators := make([]*decorator, 0, len(c.decorators))
+	for _, ds := range c.decorators {
+		decorators = append(decorators, ds...)
+	}
+	c.mu.Unlock()
+	for _, d := range decorators {
+		if _, err := _c.callDecorator(d, reflect.Zero(d.t).Interface()); err != nil {
 			vErr.errs = append(vErr.errs, err)
 		}
 	}
@@ -178,6 +862,118 @@ func (c *container) Validate() error {
 	return nil
 }
 
+// keys returns the registered keys in registration order.
+func (c *container) keys() []dKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]dKey, 0, len(c.dependencies))
+	for key := range c.dependencies {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.dependencies[keys[i]].order < c.dependencies[keys[j]].order
+	})
+	return keys
+}
+
+// dependencyOrder sorts keys so that every key comes after the keys it depends on.
+func (c *container) dependencyOrder(keys []dKey) []dKey {
+	wanted := make(map[dKey]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+	visited := make(map[dKey]bool)
+	ordered := make([]dKey, 0, len(keys))
+	var visit func(key dKey)
+	visit = func(key dKey) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		if dep, ok := c.local(key); ok && !dep.isParam {
+			fnT := dep.value.Type()
+			for i := 0; i < fnT.NumIn(); i++ {
+				visit(argKey(fnT.In(i)))
+			}
+		}
+		if wanted[key] {
+			ordered = append(ordered, key)
+		}
+	}
+	for _, key := range keys {
+		visit(key)
+	}
+	return ordered
+}
+
+// Bindings returns the registrations of c and its parents.
+func (c *container) Bindings() []Binding {
+	var bindings []Binding
+	seen := make(map[dKey]bool)
+	for level, cc := 0, c; cc != nil; level, cc = level+1, cc.parent {
+		for _, key := range cc.keys() {
+			dep, _ := cc.local(key)
+			b := Binding{
+				Key:      key.export(),
+				Level:    level,
+				Param:    dep.isParam,
+				Shadowed: seen[key],
+			}
+			if !dep.isParam {
+				fnT := dep.value.Type()
+				for i := 0; i < fnT.NumIn(); i++ {
+					b.Dependencies = append(b.Dependencies, argKey(fnT.In(i)).export())
+				}
+			}
+			seen[key] = true
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings
+}
+
+// assertNotCaptive makes sure that a singleton does not depend on a dependency that lives shorter.
+func (c *container) assertNotCaptive(key dKey, dep *dependency) error {
+	if c.lifetimeOf(dep) != lifetimeSingleton {
+		return nil
+	}
+	// the parameters of All, Future and variadic types are expanded to the keys they are resolved with.
+	var inKeys []dKey
+	for cc := c; cc != nil; cc = cc.parent {
+		cc.mu.Lock()
+		for i := 0; i < dep.value.Type().NumIn(); i++ {
+			inKeys = append(inKeys, paramKeys(cc, nil, dep, i)...)
+		}
+		cc.mu.Unlock()
+	}
+	for _, inKey := range inKeys {
+		inDep, owner := c.lookup(inKey)
+		if inDep == nil {
+			continue
+		}
+		if lt := owner.lifetimeOf(inDep); lt != lifetimeSingleton {
+			return &captiveDependencyError{key, inKey, lt}
+		}
+	}
+	return nil
+}
+
+// dry returns a clone of c that does not call the constructors.
+func (c *container) dry() *container {
+	_c := c.clone(false)
+	_c.cache = false
+	for _, dep := range _c.dependencies {
+		dep.lifetime = lifetimeDefault
+	}
+	_c.invoker = dryInvoker
+	_c.dryRun = true
+	_c.observer = nopObserver{}
+	if c.parent != nil {
+		_c.parent = c.parent.dry()
+	}
+	return _c
+}
+
 func (c *container) applyRegisterOption(key *dKey, dep *dependency, options *registerOptions) error {
 	if err := key.applyOption(options); err != nil {
 		return err
@@ -189,7 +985,7 @@ func (c *container) applyRegisterOption(key *dKey, dep *dependency, options *reg
 }
 
 func (c *container) findDep(key dKey) (*dependency, error) {
-	dep, ok := c.dependencies[key]
+	dep, ok := c.local(key)
 	if !ok {
 		return nil, &notFoundRegisterError{key}
 	}
@@ -213,48 +1009,368 @@ func (c *container) pick(t reflect.Type, values []any) (ret any, err error) {
 	return
 }
 
-// call returns result of executing the constructor function.
-func (c *container) call(fn reflect.Value) ([]any, error) {
-	fnT := fn.Type()
+// call returns result of executing the constructor function of key, and the arguments it was called with.
+func (c *container) call(key dKey, dep *dependency) ([]any, []reflect.Value, error) {
+	c.observer.BeforeConstruct(key.export())
+	start := time.Now()
+	results, args, err := c.invoke(dep.value, dep.fromGroup, c.invokerOf(key, dep))
+	if err != nil {
+		c.observer.AfterConstruct(key.export(), time.Since(start), err)
+		return nil, nil, err
+	}
+	_, err = c.pick(key.t, results)
+	c.observer.AfterConstruct(key.export(), time.Since(start), err)
+	return results, args, nil
+}
+
+// resolveArgs resolves the arguments of fnT.
+// the variadic parameter is filled with the dependencies of group,
+// or with all the bindings of its element type if group is empty.
+func (c *container) resolveArgs(fnT reflect.Type, group string) ([]reflect.Value, error) {
 	args := make([]reflect.Value, fnT.NumIn())
 	for i := range args {
-		inT := fnT.In(i)
-		arg, err := c.Resolve(dKey{t: inT})
+		if fnT.IsVariadic() && i == len(args)-1 {
+			arg, err := c.resolveVariadic(fnT.In(i), group)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = arg
+			continue
+		}
+		arg, err := c.resolveArg(fnT.In(i))
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// resolveVariadic resolves the slice of the variadic parameter t.
+func (c *container) resolveVariadic(t reflect.Type, group string) (reflect.Value, error) {
+	var keys []dKey
+	if group == "" {
+		keys = c.keysOf(t.Elem())
+	} else {
+		keys = c.groupKeys(t.Elem(), group)
+	}
+	values := reflect.MakeSlice(t, 0, len(keys))
+	for _, key := range keys {
+		v, err := c.Resolve(key)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ev := reflect.Zero(t.Elem())
+		if v != nil {
+			ev = reflect.ValueOf(v)
+		}
+		values = reflect.Append(values, ev)
+	}
+	return values, nil
+}
+
+// groupKeys returns the keys of the dependencies of group assignable to t in registration order.
+// the keys shadowed by a child container are skipped.
+func (c *container) groupKeys(t reflect.Type, group string) []dKey {
+	var keys []dKey
+	seen := make(map[dKey]bool)
+	for cc := c; cc != nil; cc = cc.parent {
+		for _, key := range cc.keys() {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if dep, _ := cc.local(key); dep.group == group && key.t.AssignableTo(t) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// resolveArg resolves an argument of type t of the functions called by the container.
+func (c *container) resolveArg(t reflect.Type) (reflect.Value, error) {
+	if t.Implements(futureParamType) {
+		return c.resolveFuture(t)
+	}
+	// constructors can take the context of the container unless context.Context is registered.
+	if t == contextType {
+		if dep, _ := c.lookup(dKey{t: t}); dep == nil {
+			return reflect.ValueOf(context.Context(c)), nil
+		}
+	}
+	if t.Implements(allParamType) {
+		elemT := reflect.Zero(t).Interface().(allParam).elemType()
+		values, err := c.ResolveAll(elemT, nil)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		m := reflect.MakeMapWithSize(t, len(values))
+		for tag, v := range values {
+			ev := reflect.Zero(elemT)
+			if v != nil {
+				ev = reflect.ValueOf(v)
+			}
+			m.SetMapIndex(reflect.ValueOf(tag), ev)
+		}
+		return m, nil
+	}
+	arg, err := c.Resolve(dKey{t: t})
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if arg == nil {
+		return reflect.Zero(t), nil
+	}
+	return reflect.ValueOf(arg), nil
+}
+
+// ResolveAll resolves all bindings of t whose tag matches, keyed by tag.
+// if match is nil, all tags match. bindings of the parent containers are included unless they are shadowed.
+func (c *container) ResolveAll(t reflect.Type, match func(tag string) bool) (map[string]any, error) {
+	values := make(map[string]any)
+	for _, key := range c.keysOf(t) {
+		if match != nil && !match(key.tag) {
+			continue
+		}
+		v, err := c.Resolve(key)
 		if err != nil {
 			return nil, err
 		}
-		args[i] = reflect.ValueOf(arg)
+		values[key.tag] = v
+	}
+	return values, nil
+}
+
+// keysOf returns the keys of type t registered in c and its parents.
+func (c *container) keysOf(t reflect.Type) []dKey {
+	var keys []dKey
+	seen := make(map[string]bool)
+	for cc := c; cc != nil; cc = cc.parent {
+		for _, key := range cc.keys() {
+			if key.t != t || seen[key.tag] {
+				continue
+			}
+			seen[key.tag] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// invoke resolves the arguments of fn and executes it with inv. it returns the results and the arguments.
+// the variadic parameter of fn is filled from group.
+func (c *container) invoke(fn reflect.Value, group string, inv invoker) ([]any, []reflect.Value, error) {
+	args, err := c.resolveArgs(fn.Type(), group)
+	if err != nil {
+		return nil, nil, err
+	}
+	rets, err := inv(fn, args)
+	if err != nil {
+		return nil, nil, err
 	}
 	results := make([]any, fn.Type().NumOut())
-	for i, ret := range c.invoker(fn, args) {
+	for i, ret := range rets {
 		results[i] = ret.Interface()
 	}
-	return results, nil
+	return results, args, nil
 }
 
-// commit stores generated dependencies if cache option is enabled.
-func (c *container) commit(sKey dKey, values []any) error {
+// invokerOf returns the invoker of dep applying its Timeout and Retry options.
+func (c *container) invokerOf(key dKey, dep *dependency) invoker {
+	inv := c.invoker
+	if c.dryRun {
+		return inv
+	}
+	if dep.timeout > 0 {
+		inv = timeoutInvoker(inv, key, dep.timeout)
+	}
+	if dep.retry > 1 {
+		inv = retryInvoker(inv, c, key, dep.retry, dep.backoff, func(attempt int, err error) {
+			if ro, ok := c.observer.(RetryObserver); ok {
+				ro.OnRetry(key.export(), attempt, err)
+			}
+		})
+	}
+	return inv
+}
+
+// valueKey returns the key of value built for sKey.
+func (c *container) valueKey(sKey dKey, value any) dKey {
+	key := dKey{t: reflect.TypeOf(value), tag: sKey.tag}
+	if sKey.IsInterfaceType() {
+		key.t = sKey.Type()
+	}
+	return key
+}
+
+// commit stores generated dependencies according to their lifetime.
+func (c *container) commit(sKey dKey, values []any, b *buildRecord) error {
+	type result struct {
+		key   dKey
+		dep   *dependency
+		owner *container
+		value any
+	}
+	results := make([]result, 0, len(values))
 	for _, value := range values {
 		if value == nil {
 			continue
 		}
-		key := dKey{t: reflect.TypeOf(value), tag: sKey.tag}
-		if sKey.IsInterfaceType() {
-			key.t = sKey.Type()
+		key := c.valueKey(sKey, value)
+		dep, owner := c.lookup(key)
+		if dep == nil {
+			return &notFoundRegisterError{key}
 		}
-		dep, err := c.findDep(key)
-		if err != nil {
-			return err
-		}
-		if dep.cache == nil {
-			if c.cache {
-				dep.instance = value
+		results = append(results, result{key, dep, owner, value})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range results {
+		key, dep, owner, value := r.key, r.dep, r.owner, r.value
+		inst := instance{key: key, value: value, dep: dep, record: b}
+		switch owner.lifetimeOf(dep) {
+		case lifetimeSingleton:
+			if owner != c {
+				continue
+			}
+			built := dep.instance != nil
+			dep.instance = value
+			if built {
+				continue
+			}
+		case lifetimeScoped:
+			if _, ok := c.scoped[key]; ok {
+				continue
+			}
+			c.scoped[key] = value
+		case lifetimePooled:
+			if owner != c {
+				continue
 			}
+			// the requested value is reserved by resolve, and the other values built with it are idle.
+			if key != sKey {
+				p := c.pool(key, dep)
+				if p.full() {
+					continue
+				}
+				p.created++
+				p.put(value)
+			}
+		case lifetimeThreadLocal:
+			if owner != c {
+				continue
+			}
+			if c.locals[key] == nil {
+				c.locals[key] = make(map[uint64]any)
+			}
+			inst.gid = goroutineID()
+			c.locals[key][inst.gid] = value
+		default:
 			continue
 		}
-		if *dep.cache {
-			dep.instance = value
+		c.built = append(c.built, inst)
+	}
+	return nil
+}
+
+func (c *container) pool(key dKey, dep *dependency) *pool {
+	p, ok := c.pools[key]
+	if !ok {
+		p = &pool{size: dep.poolSize}
+		c.pools[key] = p
+	}
+	return p
+}
+
+// Release returns the pooled instance to the pool, or disposes the thread local instance of the current goroutine.
+// it does nothing for the other lifetimes.
+func (c *container) Release(key dKey, value any) error {
+	dep, owner := c.lookup(key)
+	if dep == nil {
+		return &notFoundRegisterError{key}
+	}
+	var released []instance
+	owner.mu.Lock()
+	switch owner.lifetimeOf(dep) {
+	case lifetimePooled:
+		if p, ok := owner.pools[key]; ok {
+			p.put(value)
+		}
+	case lifetimeThreadLocal:
+		gid := goroutineID()
+		if _, ok := owner.locals[key][gid]; ok {
+			delete(owner.locals[key], gid)
+			released = owner.unbuild(key, gid)
+		}
+	}
+	owner.mu.Unlock()
+	return disposeAll(context.Background(), released)
+}
+
+// unbuild removes the thread local instances of key built in the goroutine gid from c.built and returns them. c.mu must be held.
+func (c *container) unbuild(key dKey, gid uint64) []instance {
+	var removed []instance
+	built := make([]instance, 0, len(c.built))
+	for _, inst := range c.built {
+		if inst.key == key && inst.gid == gid && !inst.inherited {
+			removed = append(removed, inst)
+			continue
+		}
+		built = append(built, inst)
+	}
+	c.built = built
+	return removed
+}
+
+// Close disposes the cached instances in reverse creation order and closes the container.
+// an instance is disposed by the DisposeWith option, or by its Close() error, Shutdown(context.Context) error
+// or Stop() method. ctx is given to Shutdown, and the instances are disposed even if ctx is done.
+// Resolve of a closed container returns ErrContainerClosed.
+// the instances of the parent are not disposed.
+func (c *container) Close(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	built := c.discard()
+	c.mu.Unlock()
+	return disposeAll(ctx, built)
+}
+
+// disposeAll disposes the built instances in reverse order, skipping the inherited ones.
+// every instance is disposed even if ctx is done, and the error of ctx is added to the returned error.
+func disposeAll(ctx context.Context, built []instance) error {
+	var errs []error
+	for i := len(built) - 1; i >= 0; i-- {
+		inst := built[i]
+		if inst.inherited {
+			continue
+		}
+		if err := dispose(ctx, inst.dep, inst.value); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", inst.key.export(), err))
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func dispose(ctx context.Context, dep *dependency, value any) error {
+	if dep.dispose != nil {
+		return dep.dispose.fn(value)
+	}
+	switch v := value.(type) {
+	case io.Closer:
+		return v.Close()
+	case interface{ Shutdown(context.Context) error }:
+		return v.Shutdown(ctx)
+	case interface{ Stop() }:
+		v.Stop()
+	}
 	return nil
 }
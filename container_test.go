@@ -1,7 +1,10 @@
 package sticky
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,7 +18,7 @@ func TestResolveCache(t *testing.T) {
 
 	tests := []struct {
 		name   string
-		input  register
+		input  Registration
 		assert func(assert.TestingT, bool, ...any) bool
 	}{
 		{
@@ -130,3 +133,279 @@ func TestDecorate(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "decorated", v.(A).string)
 }
+
+func TestDecorator(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ string }
+	type B struct{ string }
+
+	t.Run("chain", func(t *testing.T) {
+		c := newContainer()
+		require.NoError(t, c.Register(Param("!", "")))
+		require.NoError(t, c.Register(Decorator(func(a *A) *A {
+			a.string += "1"
+			return a
+		})))
+		require.NoError(t, c.Register(Decorator(func(a *A, s string) (*A, error) {
+			a.string += "2" + s
+			return a, nil
+		})))
+		require.NoError(t, c.Register(Constructor(func() *A { return &A{"a"} })))
+		require.NoError(t, c.Register(Constructor(func() *A { return &A{"b"} }, Tag("b"))))
+
+		v, err := c.Resolve(dKey{t: reflect.TypeOf(&A{})})
+		require.NoError(t, err)
+		assert.Equal(t, &A{"a12!"}, v)
+		v, err = c.Resolve(dKey{t: reflect.TypeOf(&A{}), tag: "b"})
+		require.NoError(t, err)
+		assert.Equal(t, &A{"b12!"}, v)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		c := newContainer()
+		require.NoError(t, c.Register(Decorator(func(b B) B {
+			return B{b.string + "!"}
+		}, Tag("decorated"))))
+		require.NoError(t, c.Register(Constructor(func() B { return B{"b"} })))
+		require.NoError(t, c.Register(Constructor(func() B { return B{"b"} }, Tag("decorated"))))
+
+		v, err := c.Resolve(dKey{t: reflect.TypeOf(B{})})
+		require.NoError(t, err)
+		assert.Equal(t, B{"b"}, v)
+		v, err = c.Resolve(dKey{t: reflect.TypeOf(B{}), tag: "decorated"})
+		require.NoError(t, err)
+		assert.Equal(t, B{"b!"}, v)
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		c := newContainer()
+		count := 0
+		require.NoError(t, c.Register(Decorator(func(b B) B {
+			count++
+			return b
+		})))
+		require.NoError(t, c.Register(Constructor(func() B { return B{"b"} }, Cache(false))))
+		for i := 0; i < 3; i++ {
+			_, err := c.Resolve(dKey{t: reflect.TypeOf(B{})})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := newContainer()
+		require.NoError(t, c.Register(Decorator(func(b B) (B, error) {
+			return b, errors.New("dummy error")
+		})))
+		require.NoError(t, c.Register(Constructor(func() B { return B{"b"} })))
+		_, err := c.Resolve(dKey{t: reflect.TypeOf(B{})})
+		assert.Equal(t, errors.New("dummy error"), err)
+	})
+
+	t.Run("param", func(t *testing.T) {
+		c := newContainer()
+		require.NoError(t, c.Register(Decorator(func(s string) string { return s + "!" }, Tag("url"))))
+		require.NoError(t, c.Register(Supply("http://localhost", Tag("url"))))
+		require.NoError(t, c.Register(Param(B{"b"}, "")))
+		require.NoError(t, c.Register(Decorator(func(b B) B { return B{b.string + "!"} })))
+
+		v, err := c.Resolve(dKey{t: reflect.TypeOf(""), tag: "url"})
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost!", v)
+		v, err = c.Resolve(dKey{t: reflect.TypeOf(B{})})
+		require.NoError(t, err)
+		assert.Equal(t, B{"b!"}, v)
+	})
+
+	t.Run("invalid decorator", func(t *testing.T) {
+		c := newContainer()
+		var e *invalidDecoratorError
+		assert.True(t, errors.As(c.Register(Decorator(func(a A) B { return B{} })), &e))
+		assert.True(t, errors.As(c.Register(Decorator("dummy")), &e))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		c := newContainer()
+		require.NoError(t, c.Register(Decorator(func(b B, a A) B { return b })))
+		require.NoError(t, c.Register(Constructor(func() B { return B{"b"} })))
+		require.Error(t, c.Validate())
+	})
+}
+
+func TestScope(t *testing.T) {
+	type A struct{ string }
+	type B struct{ *A }
+
+	c := newContainer()
+	require.NoError(t, c.Register(Constructor(func() *A { return &A{"parent"} })))
+	require.NoError(t, c.Register(Constructor(func(a *A) *B { return &B{a} })))
+
+	scope := c.Scope().(*container)
+	require.NoError(t, scope.Register(Constructor(func() *A { return &A{"child"} })))
+
+	a, err := scope.Resolve(dKey{t: reflect.TypeOf(&A{})})
+	require.NoError(t, err)
+	assert.Equal(t, &A{"child"}, a)
+
+	// B is built in the parent, so it depends on the parent A.
+	b, err := scope.Resolve(dKey{t: reflect.TypeOf(&B{})})
+	require.NoError(t, err)
+	assert.Equal(t, &B{&A{"parent"}}, b)
+	b2, err := c.Resolve(dKey{t: reflect.TypeOf(&B{})})
+	require.NoError(t, err)
+	assert.Same(t, b, b2)
+
+	require.NoError(t, scope.Validate())
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ string }
+	type B struct{ string }
+	keyA := dKey{t: reflect.TypeOf(&A{})}
+	keyB := dKey{t: reflect.TypeOf(B{})}
+
+	c := newContainer()
+	require.NoError(t, c.Register(Constructor(func() *A { return &A{"a"} })))
+	a, err := c.Resolve(keyA)
+	require.NoError(t, err)
+
+	t.Run("clone", func(t *testing.T) {
+		clone := c.Clone().(*container)
+		require.NoError(t, clone.Register(Constructor(func() B { return B{"b"} })))
+		_, err := c.Resolve(keyB)
+		assert.Error(t, err)
+
+		built, err := clone.Built(keyA)
+		require.NoError(t, err)
+		assert.False(t, built)
+		v, err := clone.Resolve(keyA)
+		require.NoError(t, err)
+		assert.NotSame(t, a, v)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		snapshot := c.Snapshot().(*container)
+		v, err := snapshot.Resolve(keyA)
+		require.NoError(t, err)
+		assert.Same(t, a, v)
+
+		restore, err := snapshot.Override(Constructor(func() *A { return &A{"override"} }))
+		require.NoError(t, err)
+		defer func() { assert.NoError(t, restore()) }()
+		v, err = c.Resolve(keyA)
+		require.NoError(t, err)
+		assert.Same(t, a, v)
+	})
+
+	t.Run("validate has no side effects", func(t *testing.T) {
+		c := newContainer()
+		require.NoError(t, c.Register(Constructor(func() *A { return &A{"a"} }, Cache(true))))
+		require.NoError(t, c.Validate())
+		v, err := c.Resolve(keyA)
+		require.NoError(t, err)
+		assert.Equal(t, &A{"a"}, v)
+	})
+}
+
+type testCloser struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (c *testCloser) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return c.err
+}
+
+type testStopper struct{ stopped bool }
+
+func (s *testStopper) Stop() { s.stopped = true }
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ *testCloser }
+	type B struct{ *testCloser }
+	type C struct{ *testCloser }
+
+	var closed []string
+	c := newContainer()
+	require.NoError(t, c.Register(Constructor(func() *A {
+		return &A{&testCloser{name: "a", closed: &closed}}
+	})))
+	require.NoError(t, c.Register(Constructor(func(a *A) *B {
+		return &B{&testCloser{name: "b", closed: &closed, err: errors.New("b error")}}
+	})))
+	require.NoError(t, c.Register(Constructor(func() *C {
+		return &C{&testCloser{name: "c", closed: &closed}}
+	}, DisposeWith(func(c *C) error {
+		closed = append(closed, "dispose c")
+		return nil
+	}))))
+	require.NoError(t, c.Register(Constructor(func() *testStopper { return &testStopper{} })))
+
+	_, err := c.Resolve(dKey{t: reflect.TypeOf(&C{})})
+	require.NoError(t, err)
+	_, err = c.Resolve(dKey{t: reflect.TypeOf(&B{})})
+	require.NoError(t, err)
+	s, err := c.Resolve(dKey{t: reflect.TypeOf(&testStopper{})})
+	require.NoError(t, err)
+
+	err = c.Close(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "b error")
+	assert.Equal(t, []string{"b", "a", "dispose c"}, closed)
+	assert.True(t, s.(*testStopper).stopped)
+
+	_, err = c.Resolve(dKey{t: reflect.TypeOf(&A{})})
+	assert.ErrorIs(t, err, ErrContainerClosed)
+	assert.NoError(t, c.Close(context.Background()))
+
+	t.Run("snapshot", func(t *testing.T) {
+		var closed []string
+		c := newContainer()
+		require.NoError(t, c.Register(Constructor(func() *A {
+			return &A{&testCloser{name: "a", closed: &closed}}
+		})))
+		require.NoError(t, c.Register(Constructor(func() *B {
+			return &B{&testCloser{name: "b", closed: &closed}}
+		})))
+		_, err := c.Resolve(dKey{t: reflect.TypeOf(&A{})})
+		require.NoError(t, err)
+
+		snapshot := c.Snapshot().(*container)
+		_, err = snapshot.Resolve(dKey{t: reflect.TypeOf(&B{})})
+		require.NoError(t, err)
+		require.NoError(t, snapshot.Close(context.Background()))
+		assert.Equal(t, []string{"b"}, closed, "the instances inherited from the original are not disposed")
+
+		require.NoError(t, c.Close(context.Background()))
+		assert.Equal(t, []string{"b", "a"}, closed)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		var closed []string
+		c := newContainer()
+		require.NoError(t, c.Register(Constructor(func() *A {
+			return &A{&testCloser{name: "a", closed: &closed}}
+		})))
+		_, err := c.Resolve(dKey{t: reflect.TypeOf(&A{})})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = c.Close(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"a"}, closed)
+	})
+
+	t.Run("invalid disposer", func(t *testing.T) {
+		c := newContainer()
+		err := c.Register(Constructor(func() *A { return &A{} }, DisposeWith(func(*B) error { return nil })))
+		assert.Error(t, err)
+	})
+}
@@ -1,8 +1,8 @@
 package sticky
 
 import (
-	"errors"
 	"reflect"
+	"time"
 )
 
 type dependency struct {
@@ -10,7 +10,17 @@ type dependency struct {
 	instance   any
 	implements *reflect.Type
 	isParam    bool
-	cache      *bool
+	lifetime   lifetime
+	poolSize   int
+	retry      int
+	backoff    time.Duration
+	timeout    time.Duration
+	// group is the group the dependency belongs to, and fromGroup is the group that fills the variadic parameter.
+	group     string
+	fromGroup string
+	dispose   *disposer
+	// order is the registration order in the container.
+	order int
 }
 
 func (s *dependency) getValue() (any, bool) {
@@ -25,17 +35,65 @@ func (s *dependency) returnTypes() []reflect.Type {
 	return out
 }
 
+// valueType returns the type of the registered value.
+func (s *dependency) valueType() reflect.Type {
+	if s.isParam {
+		return s.value.Type()
+	}
+	return s.returnTypes()[0]
+}
+
 func (s *dependency) applyOption(opt *registerOptions) error {
-	s.cache = opt.Cache
+	if opt.Lifetime == lifetimePooled && opt.PoolSize < 1 {
+		return &invalidPoolSizeError{opt.PoolSize}
+	}
+	s.lifetime = opt.Lifetime
+	s.poolSize = opt.PoolSize
+	s.retry = opt.Retry
+	s.backoff = opt.Backoff
+	s.timeout = opt.Timeout
+	s.group = opt.Group
+	s.fromGroup = opt.FromGroup
+	if opt.Dispose != nil {
+		if rt := s.valueType(); !rt.AssignableTo(opt.Dispose.t) {
+			return &invalidDisposerError{rt, opt.Dispose.t}
+		}
+		s.dispose = opt.Dispose
+	}
 
 	if opt.Implements == nil {
 		return nil
 	}
 	it := *opt.Implements
-	rt := s.returnTypes()[0]
+	rt := s.valueType()
 	if !rt.Implements(it) {
-		return errors.New("not implements")
+		return &notImplementsError{rt, it}
 	}
 	s.implements = opt.Implements
 	return nil
 }
+
+type disposer struct {
+	fn func(any) error
+	t  reflect.Type
+}
+
+type generic struct {
+	factory func(t reflect.Type) any
+	tag     string
+	opts    []registerOption
+	// keys is the keys of the constructors instantiated by factory.
+	keys []dKey
+}
+
+type decorator struct {
+	value reflect.Value
+	t     reflect.Type
+	tag   string
+}
+
+type interceptor struct {
+	fn       func(any) any
+	tag      string
+	priority int
+}
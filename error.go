@@ -2,11 +2,17 @@ package sticky
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
+// ErrContainerClosed is returned when a dependency is resolved from a closed container.
+var ErrContainerClosed = errors.New("container closed")
+
 type alreadyRegisteredError struct {
 	key dKey
 }
@@ -46,6 +52,150 @@ func (e *invalidConstructorError) Error() string {
 	return fmt.Sprintf("invalid constructor. must be factory function. got=%s", e.t.Kind())
 }
 
+type notImplementsError struct {
+	t  reflect.Type
+	it reflect.Type
+}
+
+func (e *notImplementsError) Error() string {
+	return fmt.Sprintf("not implements: type=%s, interface=%s", pathString(e.t), pathString(e.it))
+}
+
+type invalidDecoratorError struct {
+	t reflect.Type
+}
+
+func (e *invalidDecoratorError) Error() string {
+	return fmt.Sprintf("invalid decorator. must be func(T, ...) T or func(T, ...) (T, error). got=%s", e.t)
+}
+
+type notInterfaceError struct {
+	t reflect.Type
+}
+
+func (e *notInterfaceError) Error() string {
+	return fmt.Sprintf("not interface: type=%s", pathString(e.t))
+}
+
+type shutdownTimeoutError struct {
+	key dKey
+}
+
+func (e *shutdownTimeoutError) Error() string {
+	return fmt.Sprintf("shutdown timeout: %s did not stop", e.key.export())
+}
+
+type invalidDisposerError struct {
+	t  reflect.Type
+	dt reflect.Type
+}
+
+func (e *invalidDisposerError) Error() string {
+	return fmt.Sprintf("invalid disposer: type=%s, argument=%s", pathString(e.t), pathString(e.dt))
+}
+
+type invalidPoolSizeError struct {
+	size int
+}
+
+func (e *invalidPoolSizeError) Error() string {
+	return fmt.Sprintf("invalid pool size. must be 1 or more. got=%d", e.size)
+}
+
+type poolExhaustedError struct {
+	key  dKey
+	size int
+}
+
+func (e *poolExhaustedError) Error() string {
+	return fmt.Sprintf("pool exhausted: %s, size=%d", e.key.export(), e.size)
+}
+
+type captiveDependencyError struct {
+	key      dKey
+	depKey   dKey
+	lifetime lifetime
+}
+
+func (e *captiveDependencyError) Error() string {
+	return fmt.Sprintf("captive dependency: singleton %s depends on %s %s", e.key.export(), e.lifetime, e.depKey.export())
+}
+
+type invalidGenericError struct {
+	key dKey
+	t   reflect.Type
+}
+
+func (e *invalidGenericError) Error() string {
+	return fmt.Sprintf("invalid generic constructor: %s, got=%s", e.key.export(), e.t)
+}
+
+type timeoutError struct {
+	key dKey
+	d   time.Duration
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("timeout: %s was not built in %s", e.key.export(), e.d)
+}
+
+func (e *timeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+type constructorPanicError struct {
+	key   dKey
+	value any
+}
+
+func (e *constructorPanicError) Error() string {
+	return fmt.Sprintf("panic: %s: %v", e.key.export(), e.value)
+}
+
+type retryError struct {
+	key  dKey
+	errs []error
+	// stopped is the error of the context that stopped the retries.
+	stopped error
+}
+
+func (e *retryError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("retry: %s failed %d attempts:", e.key.export(), len(e.errs)))
+	for i, err := range e.errs {
+		buf.WriteString(fmt.Sprintf("\n\tattempt %d: %s", i+1, err.Error()))
+	}
+	if e.stopped != nil {
+		buf.WriteString(fmt.Sprintf("\n\tstopped: %s", e.stopped.Error()))
+	}
+	return buf.String()
+}
+
+func (e *retryError) Unwrap() []error {
+	if e.stopped != nil {
+		return append(e.errs[:len(e.errs):len(e.errs)], e.stopped)
+	}
+	return e.errs
+}
+
+type manifestError struct {
+	index int
+	line  int
+	msg   string
+	err   error
+}
+
+func (e *manifestError) Error() string {
+	if e.line == 0 {
+		return fmt.Sprintf("invalid manifest: %s", e.msg)
+	}
+	return fmt.Sprintf("invalid manifest: bindings[%d] (line %d): %s", e.index, e.line, e.msg)
+}
+
+func (e *manifestError) Unwrap() error {
+	return e.err
+}
+
 type validationError struct {
 	errs []error
 }
@@ -0,0 +1,201 @@
+package sticky
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// explainer walks the resolution tree of a dependency without calling the constructors.
+type explainer struct {
+	c   *container
+	buf strings.Builder
+	// path is the keys being explained, and done is the keys already explained.
+	path   map[dKey]bool
+	done   map[dKey]bool
+	failed []string
+}
+
+// Explain returns a report of how key is resolved.
+func (c *container) Explain(key dKey) string {
+	e := &explainer{
+		c:    c,
+		path: make(map[dKey]bool),
+		done: make(map[dKey]bool),
+	}
+	e.explain(key, 0)
+	var buf strings.Builder
+	if len(e.failed) == 0 {
+		fmt.Fprintf(&buf, "%s can be resolved.\n", key.export())
+	} else {
+		fmt.Fprintf(&buf, "%s can not be resolved:\n", key.export())
+		for _, f := range e.failed {
+			fmt.Fprintf(&buf, "\t%s\n", f)
+		}
+	}
+	buf.WriteString("\n")
+	buf.WriteString(e.buf.String())
+	return buf.String()
+}
+
+func (e *explainer) line(depth int, format string, args ...any) {
+	e.buf.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(&e.buf, format, args...)
+	e.buf.WriteString("\n")
+}
+
+func (e *explainer) explain(key dKey, depth int) {
+	name := key.export().String()
+	if e.path[key] {
+		e.line(depth, "%s: CYCLE", name)
+		e.failed = append(e.failed, fmt.Sprintf("%s: cycle dependency", name))
+		return
+	}
+	if e.done[key] {
+		e.line(depth, "%s: (see above)", name)
+		return
+	}
+	e.done[key] = true
+
+	dep, owner := e.c.lookup(key)
+	source := ""
+	if dep == nil && key.t.Implements(namedParamType) {
+		delete(e.done, key)
+		e.explain(argKey(key.t), depth)
+		return
+	}
+	if dep == nil {
+		fn := e.instantiation(key)
+		if fn == nil {
+			e.line(depth, "%s: NOT FOUND", name)
+			e.failed = append(e.failed, fmt.Sprintf("%s: not found register", name))
+			for _, s := range e.suggestions(key) {
+				e.line(depth+2, "%s", s)
+			}
+			return
+		}
+		dep = &dependency{value: reflect.ValueOf(fn)}
+		owner = e.c
+		source = " generic"
+	}
+	if owner != e.c {
+		source += " parent"
+	}
+	if dep.isParam {
+		e.line(depth, "%s: param%s", name, source)
+		return
+	}
+	e.line(depth, "%s: %s %s%s (%s)", name, funcName(dep.value), owner.lifetimeOf(dep), source, funcLocation(dep.value))
+
+	e.path[key] = true
+	defer delete(e.path, key)
+	fnT := dep.value.Type()
+	for i := 0; i < fnT.NumIn(); i++ {
+		inT := fnT.In(i)
+		switch {
+		case fnT.IsVariadic() && i == fnT.NumIn()-1:
+			var keys []dKey
+			if dep.fromGroup == "" {
+				e.line(depth+1, "...%s: all bindings", pathString(inT.Elem()))
+				keys = e.c.keysOf(inT.Elem())
+			} else {
+				e.line(depth+1, "...%s: group %q", pathString(inT.Elem()), dep.fromGroup)
+				keys = e.c.groupKeys(inT.Elem(), dep.fromGroup)
+			}
+			for _, k := range keys {
+				e.explain(k, depth+2)
+			}
+		case inT == contextType && e.contextFallback():
+			e.line(depth+1, "%s: context of the container", dKey{t: inT}.export())
+		case inT.Implements(futureParamType):
+			elemT := reflect.Zero(inT).Interface().(futureParam).futureElemType()
+			e.explain(dKey{t: elemT}, depth+1)
+		case inT.Implements(allParamType):
+			elemT := reflect.Zero(inT).Interface().(allParam).elemType()
+			e.line(depth+1, "%s: all bindings", pathString(inT))
+			for _, k := range e.c.keysOf(elemT) {
+				e.explain(k, depth+2)
+			}
+		default:
+			e.explain(argKey(inT), depth+1)
+		}
+	}
+}
+
+// contextFallback reports whether the context.Context arguments are resolved with the container,
+// which is the case unless context.Context is registered.
+func (e *explainer) contextFallback() bool {
+	dep, _ := e.c.lookup(dKey{t: contextType})
+	return dep == nil
+}
+
+// instantiation returns the constructor of key built by the generic factories without registering it.
+func (e *explainer) instantiation(key dKey) any {
+	for cc := e.c; cc != nil; cc = cc.parent {
+		for _, g := range cc.genericFactories() {
+			if g.tag != key.tag {
+				continue
+			}
+			if fn := g.factory(key.t); fn != nil {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+// suggestions returns the registered bindings that look like key.
+func (e *explainer) suggestions(key dKey) []string {
+	var suggestions []string
+	for _, k := range e.c.keysOf(key.t) {
+		suggestions = append(suggestions, fmt.Sprintf("did you mean: found %s with tag %s", pathString(k.t), quoteTag(k.tag)))
+	}
+	var alt reflect.Type
+	if key.t.Kind() == reflect.Pointer {
+		alt = key.t.Elem()
+	} else {
+		alt = reflect.PointerTo(key.t)
+	}
+	for _, k := range e.c.keysOf(alt) {
+		suggestions = append(suggestions, fmt.Sprintf("did you mean: found %s", k.export()))
+	}
+	if key.t.Kind() == reflect.Interface {
+		seen := make(map[dKey]bool)
+		for cc := e.c; cc != nil; cc = cc.parent {
+			for _, k := range cc.keys() {
+				if seen[k] || k.t == key.t || !k.t.Implements(key.t) {
+					continue
+				}
+				seen[k] = true
+				suggestions = append(suggestions, fmt.Sprintf(
+					"did you mean: found %s that implements %s but registered without Implements", k.export(), pathString(key.t)))
+			}
+		}
+	}
+	return suggestions
+}
+
+func quoteTag(tag string) string {
+	if tag == "" {
+		return `''`
+	}
+	return fmt.Sprintf("%q", tag)
+}
+
+func funcName(fn reflect.Value) string {
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		return f.Name()
+	}
+	return fn.Type().String()
+}
+
+func funcLocation(fn reflect.Value) string {
+	f := runtime.FuncForPC(fn.Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	file, line := f.FileLine(f.Entry())
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+}
@@ -0,0 +1,68 @@
+package sticky
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testExplainRepo interface{ Find() string }
+
+type testMemoryRepo struct{}
+
+func (testMemoryRepo) Find() string { return "memory" }
+
+type testExplainService struct{}
+
+func newTestExplainService(testExplainRepo, *testStore) *testExplainService {
+	return &testExplainService{}
+}
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not found", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() *testMemoryRepo { return &testMemoryRepo{} }),
+			Constructor(func() testExplainRepo { return &testMemoryRepo{} }, Tag("memory")),
+			Constructor(func() *testStore { return &testStore{} }),
+			Constructor(newTestExplainService),
+		))
+		report, err := Explain[*testExplainService](c)
+		require.NoError(t, err)
+		assert.Contains(t, report, "can not be resolved:\n\ttype=github.com/ssstoyama/sticky.testExplainRepo, tag='': not found register")
+		assert.Contains(t, report, "sticky.newTestExplainService singleton (explain_test.go:20)")
+		assert.Contains(t, report, `did you mean: found github.com/ssstoyama/sticky.testExplainRepo with tag "memory"`)
+		assert.Contains(t, report, "did you mean: found type=*github.com/ssstoyama/sticky.testMemoryRepo, tag='' that implements github.com/ssstoyama/sticky.testExplainRepo but registered without Implements")
+	})
+
+	t.Run("resolvable", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() *testMemoryRepo { return &testMemoryRepo{} }, Implements[testExplainRepo]()),
+			Constructor(func() *testStore { return &testStore{} }),
+			Constructor(newTestExplainService),
+		))
+		report, err := Explain[*testExplainService](c)
+		require.NoError(t, err)
+		assert.Contains(t, report, "type=*github.com/ssstoyama/sticky.testExplainService, tag='' can be resolved.")
+		assert.Contains(t, report, "\n  type=*github.com/ssstoyama/sticky.testStore, tag='': ")
+	})
+
+	t.Run("context", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(func(context.Context) *testStore { return &testStore{} })))
+		report, err := Explain[*testStore](c)
+		require.NoError(t, err)
+		assert.Contains(t, report, "type=*github.com/ssstoyama/sticky.testStore, tag='' can be resolved.")
+		assert.Contains(t, report, "\n  type=context.Context, tag='': context of the container\n")
+
+		require.NoError(t, Register(c, Supply(context.Background())))
+		report, err = Explain[*testStore](c)
+		require.NoError(t, err)
+		assert.Contains(t, report, "\n  type=context.Context, tag='': param\n")
+	})
+}
@@ -0,0 +1,107 @@
+package sticky
+
+import (
+	"context"
+	"reflect"
+)
+
+// Future is a dependency being built in another goroutine.
+// constructors can take Future[T] arguments so that slow dependencies are built in parallel.
+//
+// e.g.
+//   - Register(c, Constructor(func(db Future[*DB], cache Future[*Cache]) (*Service, error) {
+//     /* some code */
+//     }))
+type Future[T any] struct {
+	f *future
+}
+
+// Get waits for the dependency and returns it, or the error of building it.
+// it returns the error of ctx if ctx is done first.
+func (f Future[T]) Get(ctx context.Context) (ret T, err error) {
+	if f.f == nil {
+		return ret, &notFoundRegisterError{dKey{t: makeType[T]()}}
+	}
+	select {
+	case <-f.f.done:
+	case <-ctx.Done():
+		return ret, ctx.Err()
+	}
+	if f.f.err != nil {
+		return ret, f.f.err
+	}
+	if f.f.value != nil {
+		ret = f.f.value.(T)
+	}
+	return ret, nil
+}
+
+// Done is closed when the dependency is built.
+func (f Future[T]) Done() <-chan struct{} {
+	if f.f == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	return f.f.done
+}
+
+func (Future[T]) futureElemType() reflect.Type {
+	return makeType[T]()
+}
+
+func (f Future[T]) future() *future {
+	return f.f
+}
+
+func (f *Future[T]) setFuture(fu *future) {
+	f.f = fu
+}
+
+// futureParam is implemented by Future.
+type futureParam interface {
+	futureElemType() reflect.Type
+	future() *future
+}
+
+var futureParamType = makeType[futureParam]()
+
+type future struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// resolveAsync resolves key in another goroutine.
+// the dependency is not built if ctx is done before the goroutine starts.
+func (c *container) resolveAsync(ctx context.Context, key dKey) *future {
+	f := &future{done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		if err := ctx.Err(); err != nil {
+			f.err = err
+			return
+		}
+		f.value, f.err = c.Resolve(key)
+	}()
+	return f
+}
+
+// resolveFuture resolves the Future argument of type t.
+// the dry container resolves the dependency at once to report the errors.
+func (c *container) resolveFuture(t reflect.Type) (reflect.Value, error) {
+	key := dKey{t: reflect.Zero(t).Interface().(futureParam).futureElemType()}
+	var f *future
+	if c.dryRun {
+		if _, err := c.Resolve(key); err != nil {
+			return reflect.Value{}, err
+		}
+		f = &future{done: make(chan struct{})}
+		close(f.done)
+	} else {
+		f = c.resolveAsync(c, key)
+	}
+	v := reflect.New(t)
+	v.Interface().(interface{ setFuture(*future) }).setFuture(f)
+	return v.Elem(), nil
+}
@@ -0,0 +1,199 @@
+package sticky
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuture(t *testing.T) {
+	t.Parallel()
+
+	type DB struct{}
+	type Cache struct{}
+	type Service struct {
+		*DB
+		*Cache
+	}
+
+	t.Run("parallel", func(t *testing.T) {
+		// both constructors must reach the barrier before either returns, so they fail unless they run in parallel.
+		var calls atomic.Int32
+		var barrier sync.WaitGroup
+		barrier.Add(2)
+		reach := func() error {
+			calls.Add(1)
+			barrier.Done()
+			reached := make(chan struct{})
+			go func() {
+				barrier.Wait()
+				close(reached)
+			}()
+			select {
+			case <-reached:
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("not resolved in parallel")
+			}
+		}
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() (*DB, error) {
+				if err := reach(); err != nil {
+					return nil, err
+				}
+				return &DB{}, nil
+			}),
+			Constructor(func() (*Cache, error) {
+				if err := reach(); err != nil {
+					return nil, err
+				}
+				return &Cache{}, nil
+			}),
+			Constructor(func(db Future[*DB], cache Future[*Cache]) (*Service, error) {
+				ctx := context.Background()
+				d, err := db.Get(ctx)
+				if err != nil {
+					return nil, err
+				}
+				ch, err := cache.Get(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return &Service{d, ch}, nil
+			}),
+		))
+		require.NoError(t, Validate(c))
+
+		var wg sync.WaitGroup
+		services := make([]*Service, 5)
+		for i := range services {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				s, err := ResolveAsync[*Service](context.Background(), c).Get(context.Background())
+				assert.NoError(t, err)
+				services[i] = s
+			}(i)
+		}
+		wg.Wait()
+		assert.Equal(t, int32(2), calls.Load())
+		for _, s := range services {
+			assert.Same(t, services[0], s)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() (*DB, error) { return nil, errors.New("db error") })))
+		_, err := ResolveAsync[*DB](context.Background(), c).Get(context.Background())
+		assert.EqualError(t, err, "db error")
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		var called bool
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() *DB { called = true; return &DB{} })))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		f := ResolveAsync[*DB](ctx, c)
+		<-f.Done()
+		_, err := f.Get(context.Background())
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, called)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(func(Future[*DB]) *Service { return &Service{} })))
+		err := Validate(c)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "DB")
+	})
+}
+
+func TestResolvePanic(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	t.Run("panic", func(t *testing.T) {
+		c := New()
+		var calls atomic.Int32
+		require.NoError(t, Register(c, Constructor(func() *A {
+			if calls.Add(1) == 1 {
+				panic("boom")
+			}
+			return &A{}
+		})))
+		_, err := Resolve[*A](c)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := Resolve[*A](c)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("resolve after a panic blocked")
+		}
+	})
+
+	t.Run("resolve itself", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(func(ctx context.Context) (*B, error) {
+			_, err := Resolve[*B](ctx)
+			return &B{}, err
+		})))
+		done := make(chan error, 1)
+		go func() {
+			_, err := Resolve[*B](c)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.Error(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("resolve of itself blocked")
+		}
+	})
+}
+
+func TestConcurrentRegister(t *testing.T) {
+	t.Parallel()
+
+	type S struct{ n int }
+
+	c := New(Cache(false))
+	require.NoError(t, Register(c, Constructor(func() *S { return &S{} })))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := Resolve[*S](c)
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, Register(c,
+				Decorator(func(s *S) *S { s.n++; return s }),
+				Intercept(func(next testRoute) testRoute { return next }),
+				Generic(func(reflect.Type) any { return nil }),
+			))
+		}()
+	}
+	wg.Wait()
+}
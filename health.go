@@ -0,0 +1,122 @@
+package sticky
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthChecker is implemented by the components that report their health.
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// HealthTimeout option limits the time each health check takes. default 5 seconds.
+//
+// e.g.
+// - New(HealthTimeout(time.Second))
+func HealthTimeout(d time.Duration) *healthTimeoutOption {
+	return &healthTimeoutOption{d}
+}
+
+type healthTimeoutOption struct{ d time.Duration }
+
+func (o *healthTimeoutOption) applyContainerOption(opt *containerOptions) {
+	opt.HealthTimeout = o.d
+}
+
+// HealthReport is the result of the health checks.
+type HealthReport struct {
+	Healthy bool          `json:"healthy"`
+	Checks  []HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the result of the health check of a component.
+type HealthCheck struct {
+	Key      Key           `json:"-"`
+	Type     string        `json:"type"`
+	Tag      string        `json:"tag"`
+	Healthy  bool          `json:"healthy"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Health runs the health checks of the built singletons of c and its parents that implement HealthChecker.
+// the checks run concurrently, and each check fails if it does not return in the health timeout.
+// dependencies that are not built yet are not checked.
+func (c *container) Health(ctx context.Context) HealthReport {
+	var checkers []instance
+	seen := make(map[dKey]bool)
+	for cc := c; cc != nil; cc = cc.parent {
+		for _, inst := range cc.singletons() {
+			if seen[inst.key] {
+				continue
+			}
+			seen[inst.key] = true
+			if _, ok := inst.value.(HealthChecker); ok {
+				checkers = append(checkers, inst)
+			}
+		}
+	}
+
+	report := HealthReport{Healthy: true, Checks: make([]HealthCheck, len(checkers))}
+	var wg sync.WaitGroup
+	for i, inst := range checkers {
+		wg.Add(1)
+		go func(i int, inst instance) {
+			defer wg.Done()
+			report.Checks[i] = c.check(ctx, inst)
+		}(i, inst)
+	}
+	wg.Wait()
+	for _, check := range report.Checks {
+		if !check.Healthy {
+			report.Healthy = false
+		}
+	}
+	return report
+}
+
+func (c *container) check(ctx context.Context, inst instance) HealthCheck {
+	key := inst.key.export()
+	check := HealthCheck{Key: key, Type: pathString(key.Type), Tag: key.Tag}
+	ctx, cancel := context.WithTimeout(ctx, c.healthTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- inst.value.(HealthChecker).Check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	check.Duration = time.Since(start)
+	check.Healthy = err == nil
+	if err != nil {
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// singletons returns the built singletons of c in creation order.
+func (c *container) singletons() []instance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var singletons []instance
+	for _, inst := range c.built {
+		if dep, ok := c.dependencies[inst.key]; ok && c.lifetimeOf(dep) == lifetimeSingleton {
+			singletons = append(singletons, inst)
+		}
+	}
+	return singletons
+}
@@ -0,0 +1,63 @@
+package sticky
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testChecker struct {
+	err   error
+	delay time.Duration
+}
+
+func (c *testChecker) Check(ctx context.Context) error {
+	select {
+	case <-time.After(c.delay):
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	type DB struct{ *testChecker }
+	type Cache struct{ *testChecker }
+	type Queue struct{ *testChecker }
+	type Session struct{ *testChecker }
+
+	base := New(HealthTimeout(20 * time.Millisecond))
+	require.NoError(t, Register(base,
+		Constructor(func() *DB { return &DB{&testChecker{}} }),
+		Constructor(func() *Cache { return &Cache{&testChecker{err: errors.New("cache down")}} }),
+		Constructor(func() *Queue { return &Queue{&testChecker{delay: time.Second}} }),
+	))
+	c := base.Scope()
+	require.NoError(t, Register(c, Constructor(func() *Session { return &Session{&testChecker{}} }, Transient())))
+	for _, resolve := range []func(Container) error{
+		func(c Container) error { _, err := Resolve[*DB](c); return err },
+		func(c Container) error { _, err := Resolve[*Cache](c); return err },
+		func(c Container) error { _, err := Resolve[*Queue](c); return err },
+		func(c Container) error { _, err := Resolve[*Session](c); return err },
+	} {
+		require.NoError(t, resolve(c))
+	}
+
+	start := time.Now()
+	report := c.Health(context.Background())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.False(t, report.Healthy)
+	require.Len(t, report.Checks, 3)
+	assert.True(t, report.Checks[0].Healthy)
+	assert.Equal(t, "cache down", report.Checks[1].Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[2].Error)
+	assert.Contains(t, report.Checks[2].Type, "Queue")
+
+	assert.True(t, New().Health(context.Background()).Healthy)
+}
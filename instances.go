@@ -0,0 +1,162 @@
+package sticky
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+)
+
+// Instance describes a built singleton.
+type Instance struct {
+	Key Key `json:"-"`
+	// Type and Tag are of the key, and Concrete is the type of the instance.
+	Type     string `json:"type"`
+	Tag      string `json:"tag"`
+	Concrete string `json:"concrete"`
+	// Order is the construction order in the container that built the instance.
+	Order int `json:"order"`
+	// Level is 0 for the instances of the container itself, 1 for its parent, and so on.
+	Level int `json:"level"`
+	// BuiltAt is when the construction started, and Duration includes building the dependencies that were not built yet.
+	BuiltAt  time.Time     `json:"built_at"`
+	Duration time.Duration `json:"duration_ns"`
+	// Constructor and Location are the name and the source location of the constructor.
+	Constructor string `json:"constructor"`
+	Location    string `json:"location"`
+	// Dependencies are the instances the constructor was called with.
+	Dependencies []Wire `json:"dependencies"`
+}
+
+// Wire is a constructor argument of a built instance.
+type Wire struct {
+	Key      Key    `json:"-"`
+	Type     string `json:"type"`
+	Tag      string `json:"tag"`
+	Concrete string `json:"concrete"`
+}
+
+// buildRecord is how instances were built.
+type buildRecord struct {
+	fn       reflect.Value
+	at       time.Time
+	duration time.Duration
+	wires    []wire
+}
+
+type wire struct {
+	key   dKey
+	value any
+}
+
+// Instances returns the built singletons of c and its parents, starting from c, in construction order.
+func (c *container) Instances() []Instance {
+	var instances []Instance
+	seen := make(map[dKey]bool)
+	for level, cc := 0, c; cc != nil; level, cc = level+1, cc.parent {
+		for order, inst := range cc.singletons() {
+			if seen[inst.key] {
+				continue
+			}
+			seen[inst.key] = true
+			instances = append(instances, inst.describe(order, level))
+		}
+	}
+	return instances
+}
+
+func (inst instance) describe(order, level int) Instance {
+	key := inst.key.export()
+	i := Instance{
+		Key:          key,
+		Type:         pathString(key.Type),
+		Tag:          key.Tag,
+		Concrete:     concreteType(inst.value),
+		Order:        order,
+		Level:        level,
+		Dependencies: []Wire{},
+	}
+	if b := inst.record; b != nil {
+		i.BuiltAt = b.at
+		i.Duration = b.duration
+		i.Constructor = funcName(b.fn)
+		i.Location = funcLocation(b.fn)
+		for _, w := range b.wires {
+			wk := w.key.export()
+			i.Dependencies = append(i.Dependencies, Wire{
+				Key:      wk,
+				Type:     pathString(wk.Type),
+				Tag:      wk.Tag,
+				Concrete: concreteType(w.value),
+			})
+		}
+	}
+	return i
+}
+
+// wiring returns the keys and the instances of the arguments that the constructor of dep was called with.
+func (c *container) wiring(dep *dependency, args []reflect.Value) []wire {
+	fnT := dep.value.Type()
+	var wires []wire
+	for i, arg := range args {
+		inT := fnT.In(i)
+		switch {
+		case fnT.IsVariadic() && i == fnT.NumIn()-1:
+			var keys []dKey
+			if dep.fromGroup == "" {
+				keys = c.keysOf(inT.Elem())
+			} else {
+				keys = c.groupKeys(inT.Elem(), dep.fromGroup)
+			}
+			for j, key := range keys {
+				if j < arg.Len() {
+					wires = append(wires, wire{key, arg.Index(j).Interface()})
+				}
+			}
+		case inT.Implements(futureParamType):
+			fp := arg.Interface().(futureParam)
+			key := dKey{t: fp.futureElemType()}
+			var value any
+			if f := fp.future(); f != nil {
+				select {
+				case <-f.done:
+					value = f.value
+				default:
+				}
+			}
+			wires = append(wires, wire{key, value})
+		case inT.Implements(allParamType):
+			elemT := reflect.Zero(inT).Interface().(allParam).elemType()
+			for _, key := range c.keysOf(elemT) {
+				if v := arg.MapIndex(reflect.ValueOf(key.tag)); v.IsValid() {
+					wires = append(wires, wire{key, v.Interface()})
+				}
+			}
+		case inT.Implements(namedParamType):
+			wires = append(wires, wire{argKey(inT), arg.FieldByName("Value").Interface()})
+		default:
+			wires = append(wires, wire{argKey(inT), arg.Interface()})
+		}
+	}
+	return wires
+}
+
+func concreteType(v any) string {
+	if v == nil {
+		return "nil"
+	}
+	return pathString(reflect.TypeOf(v))
+}
+
+// DumpInstances writes the built singletons of the container as JSON.
+func DumpInstances(ctx stickyContext, w io.Writer) error {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Instances []Instance `json:"instances"`
+	}{c.Instances()})
+}
@@ -0,0 +1,71 @@
+package sticky
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstances(t *testing.T) {
+	t.Parallel()
+
+	type Config struct{}
+	type DB struct{}
+	type Session struct{}
+	type Service struct{}
+
+	base := New()
+	require.NoError(t, Register(base, Constructor(func() *Config { return &Config{} })))
+	c := New(Parent(base))
+	require.NoError(t, Register(c,
+		Param("localhost", "host"),
+		Constructor(func(*Config) *DB { return &DB{} }),
+		Constructor(func() *Session { return &Session{} }, Transient()),
+		Constructor(func(*DB, *Session, Named[string, testHost], ...testRoute) *Service { return &Service{} }),
+		Constructor(func() testRoute { return testPathRoute("/users") }, Tag("users")),
+	))
+	assert.Empty(t, c.Instances())
+
+	_, err := Resolve[*Service](c)
+	require.NoError(t, err)
+
+	instances := c.Instances()
+	require.Len(t, instances, 4)
+	db, route, service, config := instances[0], instances[1], instances[2], instances[3]
+	assert.Equal(t, "*github.com/ssstoyama/sticky.DB", db.Type)
+	assert.Equal(t, 0, db.Order)
+	assert.Equal(t, "github.com/ssstoyama/sticky.testRoute", route.Type)
+	assert.Equal(t, "github.com/ssstoyama/sticky.testPathRoute", route.Concrete)
+	assert.Equal(t, "users", route.Tag)
+	assert.Equal(t, 2, service.Order)
+	assert.Equal(t, 0, service.Level)
+	assert.Contains(t, service.Constructor, "TestInstances")
+	assert.Regexp(t, `^instances_test\.go:\d+$`, service.Location)
+	assert.False(t, service.BuiltAt.IsZero())
+	assert.GreaterOrEqual(t, service.Duration, db.Duration)
+	assert.Equal(t, []Wire{
+		{Key: db.Key, Type: db.Type, Concrete: db.Concrete},
+		{Key: Key{Type: makeType[*Session]()}, Type: "*github.com/ssstoyama/sticky.Session", Concrete: "*github.com/ssstoyama/sticky.Session"},
+		{Key: Key{Type: makeType[string](), Tag: "host"}, Type: "string", Tag: "host", Concrete: "string"},
+		{Key: route.Key, Type: route.Type, Tag: "users", Concrete: route.Concrete},
+	}, service.Dependencies)
+	assert.Equal(t, "*github.com/ssstoyama/sticky.Config", config.Type)
+	assert.Equal(t, 1, config.Level)
+
+	var buf bytes.Buffer
+	require.NoError(t, DumpInstances(c, &buf))
+	var dump struct {
+		Instances []map[string]any `json:"instances"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &dump))
+	require.Len(t, dump.Instances, 4)
+	assert.Equal(t, "*github.com/ssstoyama/sticky.Service", dump.Instances[2]["type"])
+	assert.Len(t, dump.Instances[2]["dependencies"], 4)
+}
+
+type testHost struct{}
+
+func (testHost) Tag() string { return "host" }
@@ -1,18 +1,120 @@
 package sticky
 
-import "reflect"
+import (
+	"context"
+	"reflect"
+	"time"
+)
 
-type invoker func(reflect.Value, []reflect.Value) []reflect.Value
+type invoker func(reflect.Value, []reflect.Value) ([]reflect.Value, error)
 
-func defaultInvoker(fn reflect.Value, args []reflect.Value) []reflect.Value {
-	return fn.Call(args)
+func defaultInvoker(fn reflect.Value, args []reflect.Value) ([]reflect.Value, error) {
+	if fn.Type().IsVariadic() {
+		return fn.CallSlice(args), nil
+	}
+	return fn.Call(args), nil
 }
 
-func dryInvoker(fn reflect.Value, _ []reflect.Value) []reflect.Value {
+func dryInvoker(fn reflect.Value, _ []reflect.Value) ([]reflect.Value, error) {
 	ft := fn.Type()
 	results := make([]reflect.Value, ft.NumOut())
 	for i := range results {
 		results[i] = reflect.Zero(fn.Type().Out(i))
 	}
-	return results
+	return results, nil
+}
+
+// timeoutInvoker calls fn with its context.Context arguments canceled after d,
+// and returns timeoutError if fn does not return in d.
+func timeoutInvoker(next invoker, key dKey, d time.Duration) invoker {
+	return func(fn reflect.Value, args []reflect.Value) ([]reflect.Value, error) {
+		parent := context.Background()
+		ctxIndex := -1
+		for i, arg := range args {
+			if fn.Type().In(i) == contextType && !arg.IsNil() {
+				parent, ctxIndex = arg.Interface().(context.Context), i
+			}
+		}
+		ctx, cancel := context.WithTimeout(parent, d)
+		defer cancel()
+		if ctxIndex >= 0 {
+			args = append([]reflect.Value(nil), args...)
+			args[ctxIndex] = reflect.ValueOf(ctx)
+		}
+
+		type result struct {
+			results   []reflect.Value
+			err       error
+			recovered any
+		}
+		done := make(chan result, 1)
+		go func() {
+			var r result
+			defer func() {
+				r.recovered = recover()
+				done <- r
+			}()
+			r.results, r.err = next(fn, args)
+		}()
+		select {
+		case r := <-done:
+			if r.recovered != nil {
+				panic(r.recovered)
+			}
+			return r.results, r.err
+		case <-ctx.Done():
+			return nil, &timeoutError{key, d}
+		}
+	}
+}
+
+// retryInvoker calls fn until it succeeds at most attempts times.
+// it waits backoff before the second attempt and doubles the wait for each following attempt.
+// the wait is canceled with the context.Context argument of fn, or parent if fn has none.
+func retryInvoker(next invoker, parent context.Context, key dKey, attempts int, backoff time.Duration, onRetry func(attempt int, err error)) invoker {
+	return func(fn reflect.Value, args []reflect.Value) ([]reflect.Value, error) {
+		ctx := parent
+		for i, arg := range args {
+			if fn.Type().In(i) == contextType && !arg.IsNil() {
+				ctx = arg.Interface().(context.Context)
+			}
+		}
+		var errs []error
+		wait := backoff
+		for attempt := 1; ; attempt++ {
+			results, err := next(fn, args)
+			if err == nil {
+				err = resultError(results)
+			}
+			if err == nil {
+				return results, nil
+			}
+			errs = append(errs, err)
+			if attempt >= attempts {
+				return nil, &retryError{key: key, errs: errs}
+			}
+			onRetry(attempt, err)
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, &retryError{key: key, errs: errs, stopped: ctx.Err()}
+			}
+			wait *= 2
+		}
+	}
+}
+
+// resultError returns the non-nil error in the results of a constructor.
+func resultError(results []reflect.Value) error {
+	for _, r := range results {
+		if r.Type() != errorType || r.IsNil() {
+			continue
+		}
+		return r.Interface().(error)
+	}
+	return nil
 }
+
+var errorType = makeType[error]()
@@ -0,0 +1,127 @@
+package sticky
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	type DB struct{}
+
+	t.Run("success", func(t *testing.T) {
+		attempts := 0
+		rec := NewRecorder()
+		c := New(Observe(rec))
+		require.NoError(t, Register(c, Constructor(func() (*DB, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, errors.New("connection refused")
+			}
+			return &DB{}, nil
+		}, Retry(3, time.Millisecond))))
+		_, err := Resolve[*DB](c)
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+
+		var retries []int
+		for _, e := range rec.Events() {
+			if e.Kind == EventRetry {
+				retries = append(retries, e.Attempt)
+				assert.EqualError(t, e.Err, "connection refused")
+			}
+		}
+		assert.Equal(t, []int{1, 2}, retries)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		attempts := 0
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() (*DB, error) {
+			attempts++
+			return nil, errors.New("connection refused")
+		}, Retry(2, time.Millisecond))))
+		_, err := Resolve[*DB](c)
+		require.Error(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.Contains(t, err.Error(), "failed 2 attempts:\n\tattempt 1: connection refused\n\tattempt 2: connection refused")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		c := New(BaseContext(ctx))
+		require.NoError(t, Register(c, Constructor(func() (*DB, error) {
+			attempts++
+			cancel()
+			return nil, errors.New("connection refused")
+		}, Retry(2, time.Hour))))
+		_, err := Resolve[*DB](c)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Contains(t, err.Error(), "failed 1 attempts:\n\tattempt 1: connection refused\n\tstopped: context canceled")
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("canceled by the context argument", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := New()
+		require.NoError(t, Register(c, Supply(ctx)))
+		require.NoError(t, Register(c, Constructor(func(ctx context.Context) (*DB, error) {
+			cancel()
+			return nil, errors.New("connection refused")
+		}, Retry(2, time.Hour))))
+		_, err := Resolve[*DB](c)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	type DB struct{}
+
+	t.Run("context", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(func(ctx context.Context) (*DB, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, Timeout(10*time.Millisecond))))
+		_, err := Resolve[*DB](c)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("not context aware", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() *DB {
+			<-release
+			return &DB{}
+		}, Timeout(10*time.Millisecond))))
+		_, err := Resolve[*DB](c)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "was not built in 10ms")
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		var attempts atomic.Int32
+		c := New()
+		require.NoError(t, Register(c, Constructor(func(ctx context.Context) (*DB, error) {
+			if attempts.Add(1) == 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return &DB{}, nil
+		}, Timeout(10*time.Millisecond), Retry(2, 0))))
+		_, err := Resolve[*DB](c)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+}
@@ -1,6 +1,9 @@
 package sticky
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 type stickey string
 
@@ -37,3 +40,34 @@ func (s *dKey) applyOption(opt *registerOptions) error {
 	}
 	return nil
 }
+
+func (k dKey) export() Key {
+	return Key{Type: k.t, Tag: k.tag}
+}
+
+// Key identifies a registered dependency by its type and tag.
+type Key struct {
+	Type reflect.Type
+	Tag  string
+}
+
+func (k Key) String() string {
+	tag := k.Tag
+	if tag == "" {
+		tag = `''`
+	}
+	return fmt.Sprintf("type=%s, tag=%s", pathString(k.Type), tag)
+}
+
+// Binding describes a registration.
+type Binding struct {
+	Key Key
+	// Dependencies are the keys of the constructor arguments.
+	Dependencies []Key
+	// Level is 0 for the registrations of the container itself, 1 for its parent, and so on.
+	Level int
+	// Shadowed is true if a registration of a child container hides this one.
+	Shadowed bool
+	// Param is true for the registrations of Param and Supply.
+	Param bool
+}
@@ -0,0 +1,135 @@
+package sticky
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// lifetime decides how long a built instance is reused.
+type lifetime int
+
+const (
+	// lifetimeDefault follows the Cache option of the container.
+	lifetimeDefault lifetime = iota
+	lifetimeSingleton
+	lifetimeTransient
+	lifetimeScoped
+	lifetimePooled
+	lifetimeThreadLocal
+)
+
+func (l lifetime) String() string {
+	switch l {
+	case lifetimeSingleton:
+		return "singleton"
+	case lifetimeTransient:
+		return "transient"
+	case lifetimeScoped:
+		return "scoped"
+	case lifetimePooled:
+		return "pooled"
+	case lifetimeThreadLocal:
+		return "thread local"
+	}
+	return "default"
+}
+
+// Singleton option builds the dependency once per container that registers it.
+//
+// e.g.
+// - Register(c, Constructor(/* some constructor */, Singleton()))
+func Singleton() *lifetimeOption {
+	return &lifetimeOption{lifetime: lifetimeSingleton}
+}
+
+// Transient option builds the dependency each time it is resolved.
+//
+// e.g.
+// - Register(c, Constructor(/* some constructor */, Transient()))
+func Transient() *lifetimeOption {
+	return &lifetimeOption{lifetime: lifetimeTransient}
+}
+
+// Scoped option builds the dependency once per scope. the container that resolves it is the scope,
+// so a scoped dependency registered in a parent is built and cached in each child.
+//
+// e.g.
+// - Register(c, Constructor(/* some constructor */, Scoped()))
+func Scoped() *lifetimeOption {
+	return &lifetimeOption{lifetime: lifetimeScoped}
+}
+
+// Pooled option keeps up to size instances of the dependency. size must be 1 or more.
+// Resolve returns an idle instance or builds a new one, and fails when all the instances are in use.
+// instances are returned to the pool by Release.
+//
+// e.g.
+// - Register(c, Constructor(/* some constructor */, Pooled(10)))
+func Pooled(size int) *lifetimeOption {
+	return &lifetimeOption{lifetime: lifetimePooled, poolSize: size}
+}
+
+// ThreadLocal option builds the dependency once per goroutine.
+// the instance of the current goroutine is disposed and discarded by Release. the instances are not removed
+// when their goroutines exit, so the goroutines that resolve it must call Release, or they are kept until Close.
+//
+// e.g.
+// - Register(c, Constructor(/* some constructor */, ThreadLocal()))
+func ThreadLocal() *lifetimeOption {
+	return &lifetimeOption{lifetime: lifetimeThreadLocal}
+}
+
+type lifetimeOption struct {
+	lifetime lifetime
+	poolSize int
+}
+
+func (o *lifetimeOption) applyRegisterOption(opt *registerOptions) {
+	opt.Lifetime = o.lifetime
+	opt.PoolSize = o.poolSize
+}
+
+// pool keeps the instances of a pooled dependency.
+type pool struct {
+	size    int
+	created int
+	idle    []any
+}
+
+func (p *pool) get() (any, bool) {
+	if len(p.idle) == 0 {
+		return nil, false
+	}
+	v := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+	return v, true
+}
+
+func (p *pool) put(v any) {
+	if len(p.idle) < p.created {
+		p.idle = append(p.idle, v)
+	}
+}
+
+func (p *pool) full() bool {
+	return p.created >= p.size
+}
+
+func (p *pool) clone() *pool {
+	_p := *p
+	_p.idle = append([]any(nil), p.idle...)
+	return &_p
+}
+
+// goroutineID returns the id of the current goroutine.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,183 @@
+package sticky
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifetime(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ n int }
+
+	newA := func() func() *A {
+		n := 0
+		return func() *A {
+			n++
+			return &A{n}
+		}
+	}
+
+	t.Run("singleton and transient", func(t *testing.T) {
+		c := New(Cache(false))
+		require.NoError(t, Register(c, Constructor(newA(), Singleton())))
+		a1, _ := Resolve[*A](c)
+		a2, _ := Resolve[*A](c)
+		assert.Same(t, a1, a2)
+
+		c = New()
+		require.NoError(t, Register(c, Constructor(newA(), Transient())))
+		a1, _ = Resolve[*A](c)
+		a2, _ = Resolve[*A](c)
+		assert.NotSame(t, a1, a2)
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(newA(), Scoped())))
+		s1, s2 := c.Scope(), c.Scope()
+		a1, err := Resolve[*A](s1)
+		require.NoError(t, err)
+		a1Again, _ := Resolve[*A](s1)
+		a2, _ := Resolve[*A](s2)
+		assert.Same(t, a1, a1Again)
+		assert.NotSame(t, a1, a2)
+
+		built, err := Built[*A](s1)
+		require.NoError(t, err)
+		assert.True(t, built)
+		built, err = Built[*A](c)
+		require.NoError(t, err)
+		assert.False(t, built)
+	})
+
+	t.Run("scoped with decorators and interceptors of the parent", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(newA(), Scoped()),
+			Decorator(func(a *A) *A { a.n += 10; return a }),
+			Constructor(func() testRoute { return testPathRoute("/users") }, Implements[testRoute](), Scoped()),
+			Intercept(func(next testRoute) testRoute { return testPathRoute("/api" + next.Path()) }),
+		))
+		s := c.Scope()
+		a, err := Resolve[*A](s)
+		require.NoError(t, err)
+		assert.Equal(t, 11, a.n)
+		aAgain, _ := Resolve[*A](s)
+		assert.Same(t, a, aAgain)
+		route, err := Resolve[testRoute](s)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/users", route.Path())
+	})
+
+	t.Run("pooled", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(newA(), Pooled(2))))
+		a1, err := Resolve[*A](c)
+		require.NoError(t, err)
+		a2, err := Resolve[*A](c)
+		require.NoError(t, err)
+		assert.NotSame(t, a1, a2)
+		_, err = Resolve[*A](c)
+		assert.Error(t, err)
+
+		require.NoError(t, Release(c, a1))
+		a3, err := Resolve[*A](c)
+		require.NoError(t, err)
+		assert.Same(t, a1, a3)
+	})
+
+	t.Run("invalid pool size", func(t *testing.T) {
+		c := New()
+		var e *invalidPoolSizeError
+		assert.ErrorAs(t, Register(c, Constructor(newA(), Pooled(0))), &e)
+	})
+
+	t.Run("thread local", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(newA(), ThreadLocal())))
+		a1, _ := Resolve[*A](c)
+		a1Again, _ := Resolve[*A](c)
+		assert.Same(t, a1, a1Again)
+
+		var a2 *A
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a2, _ = Resolve[*A](c)
+		}()
+		wg.Wait()
+		assert.NotSame(t, a1, a2)
+
+		require.NoError(t, Release(c, a1))
+		a3, _ := Resolve[*A](c)
+		assert.NotSame(t, a1, a3)
+	})
+
+	t.Run("thread local release", func(t *testing.T) {
+		type C struct{ *testCloser }
+
+		var closed []string
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() *C { return &C{&testCloser{name: "c", closed: &closed}} }, ThreadLocal())))
+		for i := 0; i < 10; i++ {
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := Resolve[*C](c)
+				assert.NoError(t, err)
+				assert.NoError(t, Release(c, v))
+			}()
+			wg.Wait()
+		}
+		assert.Len(t, closed, 10, "the released instances are disposed")
+		assert.Empty(t, c.Instances(), "the released instances are removed")
+
+		closed = nil
+		_, err := Resolve[*C](c)
+		require.NoError(t, err)
+		require.NoError(t, c.Close(context.Background()))
+		assert.Equal(t, []string{"c"}, closed, "the instances not released are disposed by Close")
+	})
+
+	t.Run("captive dependency", func(t *testing.T) {
+		type B struct{ *A }
+
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(newA(), Scoped()),
+			Constructor(func(a *A) *B { return &B{a} }),
+		))
+		err := Validate(c)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "captive dependency: singleton type=*github.com/ssstoyama/sticky.B")
+
+		c = New()
+		require.NoError(t, Register(c,
+			Constructor(newA(), Scoped()),
+			Constructor(func(a *A) *B { return &B{a} }, Scoped()),
+		))
+		assert.NoError(t, Validate(c))
+
+		for name, constructor := range map[string]any{
+			"all":      func(All[*A]) *B { return &B{} },
+			"future":   func(Future[*A]) *B { return &B{} },
+			"variadic": func(...*A) *B { return &B{} },
+		} {
+			c := New()
+			require.NoError(t, Register(c,
+				Constructor(newA(), Transient()),
+				Constructor(constructor),
+			))
+			err := Validate(c)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "captive dependency: singleton type=*github.com/ssstoyama/sticky.B", name)
+		}
+	})
+}
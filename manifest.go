@@ -0,0 +1,238 @@
+package sticky
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog is a registry of the named constructors, params and interfaces that manifests refer to.
+//
+// e.g.
+//   - cat := NewCatalog().Constructor("NewAPIRepository", NewAPIRepository).Param("endpoint", "http://localhost")
+//     CatalogInterface[Repository](cat, "Repository")
+type Catalog struct {
+	mu           sync.RWMutex
+	constructors map[string]any
+	params       map[string]any
+	interfaces   map[string]reflect.Type
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		constructors: make(map[string]any),
+		params:       make(map[string]any),
+		interfaces:   make(map[string]reflect.Type),
+	}
+}
+
+// Constructor adds a constructor named name. the constructor is checked when a manifest refers it.
+func (cat *Catalog) Constructor(name string, fn any) *Catalog {
+	cat.mu.Lock()
+	defer cat.mu.Unlock()
+	cat.constructors[name] = fn
+	return cat
+}
+
+// Param adds a param named name.
+func (cat *Catalog) Param(name string, value any) *Catalog {
+	cat.mu.Lock()
+	defer cat.mu.Unlock()
+	cat.params[name] = value
+	return cat
+}
+
+// CatalogInterface adds the interface T named name.
+func CatalogInterface[T any](cat *Catalog, name string) *Catalog {
+	cat.mu.Lock()
+	defer cat.mu.Unlock()
+	cat.interfaces[name] = makeType[T]()
+	return cat
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// binding is an entry of the bindings of a manifest.
+type binding struct {
+	Constructor string `yaml:"constructor"`
+	Param       string `yaml:"param"`
+	Implements  string `yaml:"implements"`
+	Tag         string `yaml:"tag"`
+	Lifetime    string `yaml:"lifetime"`
+	Group       string `yaml:"group"`
+}
+
+var bindingFields = map[string]bool{
+	"constructor": true,
+	"param":       true,
+	"implements":  true,
+	"tag":         true,
+	"lifetime":    true,
+	"group":       true,
+}
+
+var manifestLifetimes = map[string]lifetime{
+	"singleton":    lifetimeSingleton,
+	"transient":    lifetimeTransient,
+	"scoped":       lifetimeScoped,
+	"thread_local": lifetimeThreadLocal,
+}
+
+// LoadManifest registers the bindings of the YAML or JSON manifest read from r.
+// constructors, params and interfaces are referred by their names in cat.
+// all the bindings are checked before registering, and the errors of the bindings are joined.
+// nothing is registered if a binding can not be registered.
+//
+// e.g.
+//
+//	bindings:
+//	  - constructor: NewAPIRepository
+//	    implements: Repository
+//	    tag: api
+//	    lifetime: singleton
+//	  - param: endpoint
+//	    tag: endpoint
+func LoadManifest(ctx stickyContext, cat *Catalog, r io.Reader) error {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return err
+	}
+	var manifest struct {
+		Bindings []yaml.Node `yaml:"bindings"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&manifest); err != nil && err != io.EOF {
+		return &manifestError{msg: err.Error()}
+	}
+
+	cat.mu.RLock()
+	defer cat.mu.RUnlock()
+	var rters []Registration
+	var errs []error
+	for i := range manifest.Bindings {
+		rter, err := cat.registration(i, &manifest.Bindings[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rters = append(rters, rter)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if i, err := c.registerAll(rters); err != nil {
+		return &manifestError{index: i, line: manifest.Bindings[i].Line, msg: err.Error(), err: err}
+	}
+	return nil
+}
+
+// registration returns the registration of the i-th binding. cat.mu must be held.
+func (cat *Catalog) registration(i int, node *yaml.Node) (Registration, error) {
+	fail := func(format string, args ...any) error {
+		return &manifestError{index: i, line: node.Line, msg: fmt.Sprintf(format, args...)}
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fail("binding must be a mapping")
+	}
+	for j := 0; j+1 < len(node.Content); j += 2 {
+		if field := node.Content[j].Value; !bindingFields[field] {
+			return nil, fail("unknown field %q", field)
+		}
+	}
+	var b binding
+	if err := node.Decode(&b); err != nil {
+		return nil, fail("%s", err)
+	}
+
+	var opts []registerOption
+	if b.Tag != "" {
+		if err := validateTag(b.Tag); err != nil {
+			return nil, fail("invalid tag %s: %s", quoteTag(b.Tag), err)
+		}
+		opts = append(opts, Tag(b.Tag))
+	}
+	if b.Group != "" {
+		opts = append(opts, Group(b.Group))
+	}
+
+	switch {
+	case b.Constructor != "" && b.Param != "":
+		return nil, fail("constructor and param are exclusive")
+	case b.Param != "":
+		value, ok := cat.params[b.Param]
+		if !ok {
+			return nil, fail("unknown param %q, known params: %s", b.Param, strings.Join(sortedNames(cat.params), ", "))
+		}
+		if b.Implements != "" || b.Lifetime != "" {
+			return nil, fail("param %q can not have implements or lifetime", b.Param)
+		}
+		pr := Param(value, "")
+		pr.opts = opts
+		return pr, nil
+	case b.Constructor != "":
+	default:
+		return nil, fail("constructor or param is required")
+	}
+
+	fn, ok := cat.constructors[b.Constructor]
+	if !ok {
+		return nil, fail("unknown constructor %q, known constructors: %s", b.Constructor, strings.Join(sortedNames(cat.constructors), ", "))
+	}
+	if err := assertConstructor(reflect.ValueOf(fn)); err != nil {
+		return nil, fail("constructor %q: %s", b.Constructor, err)
+	}
+	if b.Lifetime != "" {
+		lt, ok := manifestLifetimes[b.Lifetime]
+		if !ok {
+			return nil, fail("unknown lifetime %q, must be singleton, transient, scoped or thread_local", b.Lifetime)
+		}
+		opts = append(opts, &lifetimeOption{lifetime: lt})
+	}
+	if b.Implements != "" {
+		it, ok := cat.interfaces[b.Implements]
+		if !ok {
+			return nil, fail("unknown interface %q, known interfaces: %s", b.Implements, strings.Join(sortedNames(cat.interfaces), ", "))
+		}
+		if it.Kind() != reflect.Interface {
+			return nil, fail("%s", &notInterfaceError{it})
+		}
+		ft := reflect.TypeOf(fn)
+		for j := 0; j < ft.NumOut(); j++ {
+			if out := ft.Out(j); out != errorType && !out.Implements(it) {
+				return nil, fail("constructor %q returns %s that does not implement %s", b.Constructor, pathString(out), pathString(it))
+			}
+		}
+		opts = append(opts, &implementsOption{t: it})
+	}
+	return Constructor(fn, opts...), nil
+}
+
+// validateTag reports whether tag can be resolved. tags of manifests must not have spaces, control characters or glob patterns.
+func validateTag(tag string) error {
+	if strings.TrimSpace(tag) != tag {
+		return errors.New("leading or trailing spaces")
+	}
+	for _, r := range tag {
+		switch {
+		case unicode.IsSpace(r) || unicode.IsControl(r):
+			return fmt.Errorf("contains %q", r)
+		case strings.ContainsRune(`*?[]\`, r):
+			return fmt.Errorf("contains glob pattern %q", r)
+		}
+	}
+	return nil
+}
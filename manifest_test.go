@@ -0,0 +1,121 @@
+package sticky
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type manifestRepository interface {
+	Find() string
+}
+
+type apiRepository struct{ endpoint string }
+
+func (r *apiRepository) Find() string { return "api " + r.endpoint }
+
+type memoryRepository struct{}
+
+func (r *memoryRepository) Find() string { return "memory" }
+
+func testCatalog() *Catalog {
+	cat := NewCatalog().
+		Constructor("NewAPIRepository", func(endpoint string) *apiRepository { return &apiRepository{endpoint} }).
+		Constructor("NewMemoryRepository", func() *memoryRepository { return &memoryRepository{} }).
+		Constructor("NewCounter", func() int { return 1 }).
+		Param("endpoint", "http://localhost")
+	return CatalogInterface[manifestRepository](cat, "Repository")
+}
+
+func TestLoadManifest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("yaml", func(t *testing.T) {
+		c := New()
+		err := LoadManifest(c, testCatalog(), strings.NewReader(`
+bindings:
+  - param: endpoint
+  - constructor: NewAPIRepository
+    implements: Repository
+    tag: api
+  - constructor: NewMemoryRepository
+    implements: Repository
+    lifetime: transient
+`))
+		require.NoError(t, err)
+		api, err := Resolve[manifestRepository](c, Tag("api"))
+		require.NoError(t, err)
+		assert.Equal(t, "api http://localhost", api.Find())
+		memory, err := Resolve[manifestRepository](c)
+		require.NoError(t, err)
+		assert.Equal(t, "memory", memory.Find())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		c := New()
+		err := LoadManifest(c, testCatalog(), strings.NewReader(`{"bindings": [
+  {"param": "endpoint", "tag": "url"},
+  {"constructor": "NewMemoryRepository", "implements": "Repository", "tag": "memory"}
+]}`))
+		require.NoError(t, err)
+		url, err := Resolve[string](c, Tag("url"))
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost", url)
+		_, err = Resolve[manifestRepository](c, Tag("memory"))
+		require.NoError(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		c := New()
+		err := LoadManifest(c, testCatalog(), strings.NewReader(`
+bindings:
+  - constructor: NewAPIRepo
+  - constructor: NewCounter
+    implements: Repository
+  - constructor: NewMemoryRepository
+    implements: Repo
+  - param: endpoint
+    tag: "my tag"
+  - constructor: NewMemoryRepository
+    lifetime: forever
+  - constructor: NewMemoryRepository
+    scope: request
+`))
+		require.Error(t, err)
+		msg := err.Error()
+		for _, want := range []string{
+			`bindings[0] (line 3): unknown constructor "NewAPIRepo", known constructors: NewAPIRepository, NewCounter, NewMemoryRepository`,
+			`bindings[1] (line 4): constructor "NewCounter" returns int that does not implement github.com/ssstoyama/sticky.manifestRepository`,
+			`bindings[2] (line 6): unknown interface "Repo", known interfaces: Repository`,
+			`bindings[3] (line 8): invalid tag "my tag": contains ' '`,
+			`bindings[4] (line 10): unknown lifetime "forever"`,
+			`bindings[5] (line 12): unknown field "scope"`,
+		} {
+			assert.Contains(t, msg, want)
+		}
+		assert.Empty(t, c.(*container).keys(), "nothing is registered when a binding is invalid")
+
+		err = LoadManifest(c, testCatalog(), strings.NewReader("bindings: [}"))
+		assert.Contains(t, err.Error(), "invalid manifest:")
+	})
+
+	t.Run("already registered", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() *memoryRepository { return &memoryRepository{} })))
+		err := LoadManifest(c, testCatalog(), strings.NewReader(`
+bindings:
+  - param: endpoint
+  - constructor: NewMemoryRepository
+`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid manifest: bindings[1] (line 4): already registered: type=*github.com/ssstoyama/sticky.memoryRepository")
+		var aErr *alreadyRegisteredError
+		assert.True(t, errors.As(err, &aErr))
+		bindings, err := Bindings(c)
+		require.NoError(t, err)
+		assert.Len(t, bindings, 1, "the bindings before the error are removed")
+	})
+}
@@ -0,0 +1,72 @@
+package sticky
+
+import "reflect"
+
+// Named is a dependency of T qualified by the marker type Q instead of a string tag.
+// Named[T, Q] resolves T tagged with the tag of Q, so the same binding can also be resolved with Tag.
+// the tag of Q is the result of its Tag() string method, or the path of Q, e.g. "github.com/user/app.Endpoint".
+//
+// e.g.
+//   - Register(c, Supply("http://localhost", Qualified[Endpoint]()))
+//   - Register(c, Constructor(func(endpoint Named[string, Endpoint]) *Client { /* some code */ }))
+//   - Resolve[Named[string, Endpoint]](c)
+type Named[T, Q any] struct {
+	Value T
+}
+
+func (Named[T, Q]) namedKey() dKey {
+	return dKey{t: makeType[T](), tag: qualifierTag[Q]()}
+}
+
+func (n *Named[T, Q]) setNamed(v any) {
+	if v != nil {
+		n.Value = v.(T)
+	}
+}
+
+// namedParam is implemented by Named.
+type namedParam interface {
+	namedKey() dKey
+}
+
+var namedParamType = makeType[namedParam]()
+
+// Qualified option tags dependencies with the tag of the qualifier Q.
+//
+// e.g.
+// - Register(c, Constructor(/* some constructor */, Qualified[Primary]()))
+func Qualified[Q any]() *tagOption {
+	return &tagOption{tag: qualifierTag[Q]()}
+}
+
+// QualifierTag returns the tag of the qualifier Q.
+func QualifierTag[Q any]() string {
+	return qualifierTag[Q]()
+}
+
+func qualifierTag[Q any]() string {
+	var q Q
+	if tagger, ok := any(q).(interface{ Tag() string }); ok {
+		return tagger.Tag()
+	}
+	return pathString(makeType[Q]())
+}
+
+// argKey returns the key that an argument of type t is resolved with.
+func argKey(t reflect.Type) dKey {
+	if t.Implements(namedParamType) {
+		return reflect.Zero(t).Interface().(namedParam).namedKey()
+	}
+	return dKey{t: t}
+}
+
+// resolveNamed resolves the Named dependency of type t.
+func (c *container) resolveNamed(t reflect.Type) (any, error) {
+	v, err := c.Resolve(argKey(t))
+	if err != nil {
+		return nil, err
+	}
+	n := reflect.New(t)
+	n.Interface().(interface{ setNamed(any) }).setNamed(v)
+	return n.Elem().Interface(), nil
+}
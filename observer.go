@@ -0,0 +1,245 @@
+package sticky
+
+import (
+	"sync"
+	"time"
+)
+
+// Observer receives notifications about what the container does at runtime.
+type Observer interface {
+	// OnRegister is called after a dependency is registered.
+	OnRegister(key Key)
+	// BeforeConstruct is called before the constructor of key and its arguments are resolved.
+	BeforeConstruct(key Key)
+	// AfterConstruct is called after the constructor of key returns.
+	// d includes the time spent building the arguments of the constructor.
+	AfterConstruct(key Key, d time.Duration, err error)
+	// OnCacheHit is called when a cached instance is returned instead of calling the constructor.
+	OnCacheHit(key Key)
+	// OnDecorate is called after an instance is decorated.
+	OnDecorate(key Key)
+}
+
+// RetryObserver is an optional interface of Observer.
+// OnRetry is called when the constructor of key registered with Retry option failed and is called again.
+// attempt is the number of the failed attempt starting from 1.
+type RetryObserver interface {
+	OnRetry(key Key, attempt int, err error)
+}
+
+// Observe option sets observers that are notified of the container activity.
+//
+// e.g.
+// - New(Observe(LogObserver(slog.Default())))
+func Observe(observers ...Observer) *observeOption {
+	return &observeOption{observers}
+}
+
+type observeOption struct{ observers []Observer }
+
+func (o *observeOption) applyContainerOption(opt *containerOptions) {
+	opt.Observers = append(opt.Observers, o.observers...)
+}
+
+func newObserver(observers []Observer) Observer {
+	switch len(observers) {
+	case 0:
+		return nopObserver{}
+	case 1:
+		return observers[0]
+	}
+	return multiObserver(observers)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) OnRegister(Key)                           {}
+func (nopObserver) BeforeConstruct(Key)                      {}
+func (nopObserver) AfterConstruct(Key, time.Duration, error) {}
+func (nopObserver) OnCacheHit(Key)                           {}
+func (nopObserver) OnDecorate(Key)                           {}
+
+type multiObserver []Observer
+
+func (m multiObserver) OnRegister(key Key) {
+	for _, o := range m {
+		o.OnRegister(key)
+	}
+}
+
+func (m multiObserver) BeforeConstruct(key Key) {
+	for _, o := range m {
+		o.BeforeConstruct(key)
+	}
+}
+
+func (m multiObserver) AfterConstruct(key Key, d time.Duration, err error) {
+	for _, o := range m {
+		o.AfterConstruct(key, d, err)
+	}
+}
+
+func (m multiObserver) OnCacheHit(key Key) {
+	for _, o := range m {
+		o.OnCacheHit(key)
+	}
+}
+
+func (m multiObserver) OnDecorate(key Key) {
+	for _, o := range m {
+		o.OnDecorate(key)
+	}
+}
+
+func (m multiObserver) OnRetry(key Key, attempt int, err error) {
+	for _, o := range m {
+		if ro, ok := o.(RetryObserver); ok {
+			ro.OnRetry(key, attempt, err)
+		}
+	}
+}
+
+// Logger is the subset of *slog.Logger methods used by LogObserver.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// LogObserver returns an Observer that writes structured logs to l.
+// *slog.Logger satisfies Logger.
+func LogObserver(l Logger) Observer {
+	return &logObserver{l}
+}
+
+type logObserver struct{ l Logger }
+
+func (o *logObserver) OnRegister(key Key) {
+	o.l.Debug("sticky: register", keyAttrs(key)...)
+}
+
+func (o *logObserver) BeforeConstruct(key Key) {
+	o.l.Debug("sticky: construct start", keyAttrs(key)...)
+}
+
+func (o *logObserver) AfterConstruct(key Key, d time.Duration, err error) {
+	args := append(keyAttrs(key), "duration", d)
+	if err != nil {
+		o.l.Error("sticky: construct failed", append(args, "error", err)...)
+		return
+	}
+	o.l.Debug("sticky: construct done", args...)
+}
+
+func (o *logObserver) OnCacheHit(key Key) {
+	o.l.Debug("sticky: cache hit", keyAttrs(key)...)
+}
+
+func (o *logObserver) OnDecorate(key Key) {
+	o.l.Debug("sticky: decorate", keyAttrs(key)...)
+}
+
+func (o *logObserver) OnRetry(key Key, attempt int, err error) {
+	o.l.Error("sticky: construct retry", append(keyAttrs(key), "attempt", attempt, "error", err)...)
+}
+
+func keyAttrs(key Key) []any {
+	return []any{"type", pathString(key.Type), "tag", key.Tag}
+}
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+const (
+	EventRegister EventKind = iota
+	EventBeforeConstruct
+	EventAfterConstruct
+	EventCacheHit
+	EventDecorate
+	EventRetry
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventRegister:
+		return "register"
+	case EventBeforeConstruct:
+		return "before_construct"
+	case EventAfterConstruct:
+		return "after_construct"
+	case EventCacheHit:
+		return "cache_hit"
+	case EventDecorate:
+		return "decorate"
+	case EventRetry:
+		return "retry"
+	}
+	return "unknown"
+}
+
+// Event is a notification recorded by Recorder.
+// Duration is set only for EventAfterConstruct, Attempt only for EventRetry,
+// and Err for both of them.
+type Event struct {
+	Kind     EventKind
+	Key      Key
+	Duration time.Duration
+	Attempt  int
+	Err      error
+}
+
+// Recorder is an Observer that keeps every notification in memory. it is useful for tests.
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewRecorder creates a Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Events returns a copy of the recorded events in the order they happened.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]Event, len(r.events))
+	copy(events, r.events)
+	return events
+}
+
+// Reset discards the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+func (r *Recorder) record(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *Recorder) OnRegister(key Key) {
+	r.record(Event{Kind: EventRegister, Key: key})
+}
+
+func (r *Recorder) BeforeConstruct(key Key) {
+	r.record(Event{Kind: EventBeforeConstruct, Key: key})
+}
+
+func (r *Recorder) AfterConstruct(key Key, d time.Duration, err error) {
+	r.record(Event{Kind: EventAfterConstruct, Key: key, Duration: d, Err: err})
+}
+
+func (r *Recorder) OnCacheHit(key Key) {
+	r.record(Event{Kind: EventCacheHit, Key: key})
+}
+
+func (r *Recorder) OnDecorate(key Key) {
+	r.record(Event{Kind: EventDecorate, Key: key})
+}
+
+func (r *Recorder) OnRetry(key Key, attempt int, err error) {
+	r.record(Event{Kind: EventRetry, Key: key, Attempt: attempt, Err: err})
+}
@@ -0,0 +1,111 @@
+package sticky
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ string }
+	type B struct{ A }
+
+	kinds := func(events []Event) []EventKind {
+		ret := make([]EventKind, len(events))
+		for i, e := range events {
+			ret[i] = e.Kind
+		}
+		return ret
+	}
+
+	t.Run("resolve", func(t *testing.T) {
+		rec := NewRecorder()
+		c := New(Observe(rec))
+		require.NoError(t, Register(c,
+			Constructor(func() A { return A{"a"} }),
+			Constructor(func(a A) *B { return &B{a} }),
+		))
+		_, err := Resolve[*B](c)
+		require.NoError(t, err)
+		_, err = Resolve[*B](c)
+		require.NoError(t, err)
+
+		events := rec.Events()
+		assert.Equal(t, []EventKind{
+			EventRegister,
+			EventRegister,
+			EventBeforeConstruct,
+			EventBeforeConstruct,
+			EventAfterConstruct,
+			EventAfterConstruct,
+			EventCacheHit,
+		}, kinds(events))
+		assert.Equal(t, reflect.TypeOf(&B{}), events[2].Key.Type)
+		assert.Equal(t, reflect.TypeOf(A{}), events[3].Key.Type)
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		rec := NewRecorder()
+		c := New(Observe(rec))
+		require.NoError(t, Register(c, Constructor(func() (A, error) {
+			return A{}, errors.New("dummy error")
+		})))
+		_, err := Resolve[A](c)
+		require.Error(t, err)
+
+		events := rec.Events()
+		require.Len(t, events, 3)
+		assert.Equal(t, EventAfterConstruct, events[2].Kind)
+		assert.Equal(t, errors.New("dummy error"), events[2].Err)
+	})
+
+	t.Run("decorate", func(t *testing.T) {
+		rec := NewRecorder()
+		c := New(Observe(rec))
+		require.NoError(t, Register(c, Constructor(func() A { return A{"a"} })))
+		require.NoError(t, Decorate(c, func(a A) (A, error) { return a, nil }))
+
+		events := rec.Events()
+		assert.Equal(t, EventDecorate, events[len(events)-1].Kind)
+	})
+
+	t.Run("validate is not observed", func(t *testing.T) {
+		rec := NewRecorder()
+		c := New(Observe(rec))
+		require.NoError(t, Register(c, Constructor(func() A { return A{"a"} })))
+		rec.Reset()
+		require.NoError(t, Validate(c))
+		assert.Empty(t, rec.Events())
+	})
+}
+
+type testLogger struct{ lines []string }
+
+func (l *testLogger) Debug(msg string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprint(append([]any{"DEBUG ", msg}, args...)...))
+}
+
+func (l *testLogger) Error(msg string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprint(append([]any{"ERROR ", msg}, args...)...))
+}
+
+func TestLogObserver(t *testing.T) {
+	type A struct{}
+
+	var l testLogger
+	c := New(Observe(LogObserver(&l)))
+	require.NoError(t, Register(c, Constructor(func() (A, error) {
+		return A{}, errors.New("dummy error")
+	})))
+	_, err := Resolve[A](c)
+	require.Error(t, err)
+	require.Len(t, l.lines, 3)
+	assert.Contains(t, l.lines[0], "sticky: register")
+	assert.Contains(t, l.lines[2], "ERROR sticky: construct failed")
+}
@@ -1,6 +1,10 @@
 package sticky
 
-import "reflect"
+import (
+	"context"
+	"reflect"
+	"time"
+)
 
 // containerOption is interface to apply option.
 type containerOption interface {
@@ -9,7 +13,11 @@ type containerOption interface {
 
 // containerOptions is for the container.
 type containerOptions struct {
-	Cache bool
+	Cache         bool
+	Observers     []Observer
+	Context       context.Context
+	Parent        Container
+	HealthTimeout time.Duration
 }
 
 // registerOption is interface to apply option.
@@ -21,11 +29,19 @@ type registerOption interface {
 type registerOptions struct {
 	Tag        string
 	Implements *reflect.Type
-	Cache      *bool
+	Lifetime   lifetime
+	PoolSize   int
+	Retry      int
+	Backoff    time.Duration
+	Timeout    time.Duration
+	Group      string
+	FromGroup  string
+	Priority   int
+	Dispose    *disposer
 }
 
-// resolveOption is interface to apply option.
-type resolveOption interface {
+// ResolveOption is interface to apply option.
+type ResolveOption interface {
 	applyResolveOption(*resolveOptions)
 }
 
@@ -70,6 +86,7 @@ func (o *implementsOption) applyRegisterOption(opt *registerOptions) {
 }
 
 // Cache option can be used to reuse the generated dependencies.
+// for a registration, Cache(true) is the same as Singleton and Cache(false) is the same as Transient.
 //
 // e.g.
 // - New(Cache(true)) // default true
@@ -85,5 +102,139 @@ func (o *cacheOption) applyContainerOption(opt *containerOptions) {
 }
 
 func (o *cacheOption) applyRegisterOption(opt *registerOptions) {
-	opt.Cache = &o.enable
+	opt.Lifetime = lifetimeTransient
+	if o.enable {
+		opt.Lifetime = lifetimeSingleton
+	}
+}
+
+// Priority option orders interceptors. interceptors with higher priority wrap the others.
+//
+// e.g.
+// - Register(c, Intercept[Repository](/* some interceptor */, Priority(10)))
+func Priority(priority int) *priorityOption {
+	return &priorityOption{priority}
+}
+
+type priorityOption struct{ priority int }
+
+func (o *priorityOption) applyRegisterOption(opt *registerOptions) {
+	opt.Priority = o.priority
+}
+
+// BaseContext option sets the context that the container delegates the context.Context methods to.
+//
+// e.g.
+// - New(BaseContext(ctx))
+func BaseContext(ctx context.Context) *baseContextOption {
+	return &baseContextOption{ctx}
+}
+
+type baseContextOption struct{ ctx context.Context }
+
+func (o *baseContextOption) applyContainerOption(opt *containerOptions) {
+	opt.Context = o.ctx
+}
+
+// Parent option makes the container a child of parent.
+// the child resolves the dependencies that are not registered in it from parent, and its registrations shadow the ones of parent.
+// dependencies resolved from parent are built and cached in parent.
+//
+// e.g.
+// - New(Parent(base))
+func Parent(parent Container) *parentOption {
+	return &parentOption{parent}
+}
+
+type parentOption struct{ parent Container }
+
+func (o *parentOption) applyContainerOption(opt *containerOptions) {
+	opt.Parent = o.parent
+}
+
+// DisposeWith option replaces how Close disposes the cached instance.
+// by default Close calls Close() error, Shutdown(context.Context) error or Stop() of the instance.
+//
+// e.g.
+// - Register(c, Constructor(NewPool, DisposeWith(func(p *Pool) error { p.Drain(); return nil })))
+func DisposeWith[T any](fn func(T) error) *disposeOption {
+	return &disposeOption{&disposer{
+		fn: func(v any) error { return fn(v.(T)) },
+		t:  makeType[T](),
+	}}
+}
+
+type disposeOption struct{ disposer *disposer }
+
+func (o *disposeOption) applyRegisterOption(opt *registerOptions) {
+	opt.Dispose = o.disposer
+}
+
+// Group option adds the dependency to the named group.
+// a variadic constructor registered with FromGroup option receives the dependencies of the group.
+//
+// e.g.
+// - Register(c, Constructor(NewHealthRoute, Group("routes")))
+func Group(name string) *groupOption {
+	return &groupOption{name}
+}
+
+type groupOption struct{ name string }
+
+func (o *groupOption) applyRegisterOption(opt *registerOptions) {
+	opt.Group = o.name
+}
+
+// FromGroup option fills the variadic parameter of the constructor with the dependencies of the named group
+// that are assignable to its element type, in registration order.
+// without FromGroup option, the variadic parameter receives all the bindings of its element type of any tag.
+//
+// e.g.
+// - Register(c, Constructor(func(routes ...Route) *Router { /* some code */ }, FromGroup("routes")))
+func FromGroup(name string) *fromGroupOption {
+	return &fromGroupOption{name}
+}
+
+type fromGroupOption struct{ name string }
+
+func (o *fromGroupOption) applyRegisterOption(opt *registerOptions) {
+	opt.FromGroup = o.name
+}
+
+// Retry option calls the constructor again when it returns an error, at most attempts times in total.
+// it waits backoff before the second attempt and doubles the wait for each following attempt.
+// the error of the last attempt lists the errors of all the attempts.
+// the wait stops when the context.Context argument of the constructor, or the container, is done,
+// and the error lists the attempts made and the error of the context.
+//
+// e.g.
+// - Register(c, Constructor(NewDB, Retry(5, 100*time.Millisecond)))
+func Retry(attempts int, backoff time.Duration) *retryOption {
+	return &retryOption{attempts, backoff}
+}
+
+type retryOption struct {
+	attempts int
+	backoff  time.Duration
+}
+
+func (o *retryOption) applyRegisterOption(opt *registerOptions) {
+	opt.Retry = o.attempts
+	opt.Backoff = o.backoff
+}
+
+// Timeout option fails the constructor if it does not return in d.
+// the context.Context argument of the constructor is canceled after d, so the constructor should return when it is done.
+// with Retry option, each attempt has its own timeout.
+//
+// e.g.
+// - Register(c, Constructor(func(ctx context.Context) (*DB, error) { /* some code */ }, Timeout(5*time.Second)))
+func Timeout(d time.Duration) *timeoutOption {
+	return &timeoutOption{d}
+}
+
+type timeoutOption struct{ d time.Duration }
+
+func (o *timeoutOption) applyRegisterOption(opt *registerOptions) {
+	opt.Timeout = o.d
 }
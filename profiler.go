@@ -0,0 +1,184 @@
+package sticky
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Profile option records how long each constructor takes to build.
+//
+// e.g.
+// - p := NewProfiler(); New(Profile(p)); ...; p.WriteText(os.Stderr)
+func Profile(p *Profiler) *observeOption {
+	return &observeOption{[]Observer{p}}
+}
+
+// ProfileEntry is the aggregated measurement of a dependency.
+// Total includes the time spent building the arguments of the constructor, Self does not.
+type ProfileEntry struct {
+	Key        Key           `json:"-"`
+	Type       string        `json:"type"`
+	Tag        string        `json:"tag"`
+	Calls      int           `json:"calls"`
+	Total      time.Duration `json:"total_ns"`
+	Self       time.Duration `json:"self_ns"`
+	Allocs     uint64        `json:"allocs"`
+	SelfAllocs uint64        `json:"self_allocs"`
+}
+
+// Profiler is an Observer that measures the constructors called by the container.
+// it reads runtime.MemStats around each constructor, so it should only be enabled while profiling.
+type Profiler struct {
+	mu sync.Mutex
+	// stacks are the constructors being built by each goroutine.
+	stacks  map[uint64][]*profileFrame
+	entries map[Key]*ProfileEntry
+	folded  map[string]time.Duration
+}
+
+type profileFrame struct {
+	key         Key
+	mallocs     uint64
+	childTime   time.Duration
+	childAllocs uint64
+}
+
+// NewProfiler creates a Profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		stacks:  make(map[uint64][]*profileFrame),
+		entries: make(map[Key]*ProfileEntry),
+		folded:  make(map[string]time.Duration),
+	}
+}
+
+func (p *Profiler) OnRegister(Key) {}
+func (p *Profiler) OnCacheHit(Key) {}
+func (p *Profiler) OnDecorate(Key) {}
+
+func (p *Profiler) BeforeConstruct(key Key) {
+	gid := goroutineID()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stacks[gid] = append(p.stacks[gid], &profileFrame{key: key, mallocs: mallocs()})
+}
+
+func (p *Profiler) AfterConstruct(key Key, d time.Duration, _ error) {
+	allocated := mallocs()
+	gid := goroutineID()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// the frames above the frame of key were not finished.
+	stack := p.stacks[gid]
+	n := len(stack) - 1
+	for n >= 0 && stack[n].key != key {
+		n--
+	}
+	if n < 0 {
+		return
+	}
+	frame := stack[n]
+	allocs := allocated - frame.mallocs
+
+	names := make([]string, n+1)
+	for i, f := range stack[:n+1] {
+		names[i] = foldedName(f.key)
+	}
+	p.folded[strings.Join(names, ";")] += d - frame.childTime
+	if n == 0 {
+		delete(p.stacks, gid)
+	} else {
+		p.stacks[gid] = stack[:n]
+		parent := stack[n-1]
+		parent.childTime += d
+		parent.childAllocs += allocs
+	}
+
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &ProfileEntry{Key: key, Type: pathString(key.Type), Tag: key.Tag}
+		p.entries[key] = entry
+	}
+	entry.Calls++
+	entry.Total += d
+	entry.Self += d - frame.childTime
+	entry.Allocs += allocs
+	entry.SelfAllocs += allocs - frame.childAllocs
+}
+
+// Entries returns the measurements sorted by self time in descending order.
+func (p *Profiler) Entries() []ProfileEntry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entries := make([]ProfileEntry, 0, len(p.entries))
+	for _, e := range p.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Self != entries[j].Self {
+			return entries[i].Self > entries[j].Self
+		}
+		return entries[i].Key.String() < entries[j].Key.String()
+	})
+	return entries
+}
+
+// WriteText writes the measurements as a table.
+func (p *Profiler) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SELF\tTOTAL\tCALLS\tSELF ALLOCS\tALLOCS\tTYPE\tTAG")
+	for _, e := range p.Entries() {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\t%s\n", e.Self, e.Total, e.Calls, e.SelfAllocs, e.Allocs, e.Type, e.Tag)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the measurements as a JSON array.
+func (p *Profiler) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(p.Entries())
+}
+
+// WriteFolded writes the self time in microseconds of each construction stack
+// in the folded format that flame graph tools accept.
+func (p *Profiler) WriteFolded(w io.Writer) error {
+	p.mu.Lock()
+	stacks := make([]string, 0, len(p.folded))
+	for stack := range p.folded {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+	lines := make([]string, len(stacks))
+	for i, stack := range stacks {
+		lines[i] = fmt.Sprintf("%s %d\n", stack, p.folded[stack].Microseconds())
+	}
+	p.mu.Unlock()
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func foldedName(key Key) string {
+	name := pathString(key.Type)
+	if key.Tag != "" {
+		name += "#" + key.Tag
+	}
+	return strings.NewReplacer(" ", "_", ";", "_").Replace(name)
+}
+
+func mallocs() uint64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.Mallocs
+}
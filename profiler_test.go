@@ -0,0 +1,93 @@
+package sticky
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	type A struct{}
+	type B struct{ A }
+
+	p := NewProfiler()
+	c := New(Profile(p))
+	require.NoError(t, Register(c,
+		Constructor(func() A {
+			time.Sleep(20 * time.Millisecond)
+			return A{}
+		}),
+		Constructor(func(a A) *B {
+			time.Sleep(10 * time.Millisecond)
+			return &B{a}
+		}),
+	))
+	_, err := Resolve[*B](c)
+	require.NoError(t, err)
+
+	entries := p.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, reflect.TypeOf(A{}), entries[0].Key.Type)
+	b := entries[1]
+	assert.Equal(t, reflect.TypeOf(&B{}), b.Key.Type)
+	assert.Equal(t, 1, b.Calls)
+	assert.GreaterOrEqual(t, b.Total, 30*time.Millisecond)
+	assert.GreaterOrEqual(t, b.Self, 10*time.Millisecond)
+	assert.Less(t, b.Self, b.Total)
+
+	var text bytes.Buffer
+	require.NoError(t, p.WriteText(&text))
+	assert.True(t, strings.HasPrefix(text.String(), "SELF"))
+
+	var js bytes.Buffer
+	require.NoError(t, p.WriteJSON(&js))
+	var decoded []ProfileEntry
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Len(t, decoded, 2)
+
+	var folded bytes.Buffer
+	require.NoError(t, p.WriteFolded(&folded))
+	lines := strings.Split(strings.TrimSpace(folded.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "*github.com/ssstoyama/sticky.B "))
+	assert.True(t, strings.HasPrefix(lines[1], "*github.com/ssstoyama/sticky.B;github.com/ssstoyama/sticky.A "))
+}
+
+func TestProfilerConcurrent(t *testing.T) {
+	type S1 struct{}
+	type S2 struct{}
+	type Root struct{}
+
+	p := NewProfiler()
+	c := New(Profile(p))
+	require.NoError(t, Register(c,
+		Constructor(func() *S1 { time.Sleep(100 * time.Millisecond); return &S1{} }),
+		Constructor(func() *S2 { time.Sleep(100 * time.Millisecond); return &S2{} }),
+		Constructor(func(s1 Future[*S1], s2 Future[*S2]) (*Root, error) {
+			if _, err := s1.Get(context.Background()); err != nil {
+				return nil, err
+			}
+			_, err := s2.Get(context.Background())
+			return &Root{}, err
+		}),
+	))
+	_, err := Resolve[*Root](c)
+	require.NoError(t, err)
+
+	for _, e := range p.Entries() {
+		if strings.HasSuffix(e.Type, "S1") || strings.HasSuffix(e.Type, "S2") {
+			assert.GreaterOrEqual(t, e.Self, 100*time.Millisecond, e.Type)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, p.WriteFolded(&buf))
+	assert.NotContains(t, buf.String(), "S2;")
+	assert.NotContains(t, buf.String(), "S1;")
+}
@@ -2,7 +2,8 @@ package sticky
 
 import "reflect"
 
-type register interface {
+// Registration is a registration of dependencies created by Constructor, Param and the other register functions.
+type Registration interface {
 	Keys() ([]dKey, error)
 	Deps() ([]*dependency, error)
 	Opts() []registerOption
@@ -50,20 +51,22 @@ func (cr *constructorRegister) Opts() []registerOption {
 
 func Param(value any, tag string) *paramRegister {
 	return &paramRegister{
-		value: value,
+		value: reflect.ValueOf(value),
 		tag:   tag,
+		opts:  make([]registerOption, 0),
 	}
 }
 
 type paramRegister struct {
 	tag   string
-	value any
+	value reflect.Value
+	opts  []registerOption
 }
 
 func (pr *paramRegister) Keys() ([]dKey, error) {
 	keys := make([]dKey, 1)
 	keys[0] = dKey{
-		t:   reflect.TypeOf(pr.value),
+		t:   pr.value.Type(),
 		tag: pr.tag,
 	}
 	return keys, nil
@@ -72,12 +75,231 @@ func (pr *paramRegister) Keys() ([]dKey, error) {
 func (pr *paramRegister) Deps() ([]*dependency, error) {
 	values := make([]*dependency, 1)
 	values[0] = &dependency{
-		value:   reflect.ValueOf(pr.value),
+		value:   pr.value,
 		isParam: true,
 	}
 	return values, nil
 }
 
 func (pr *paramRegister) Opts() []registerOption {
-	return make([]registerOption, 0)
+	return pr.opts
 }
+
+// Supply registers value as a parameter of type T. T may be an interface type.
+//
+// e.g.
+// - Register(c, Supply[io.Writer](os.Stdout, Tag("stdout")))
+func Supply[T any](value T, opts ...registerOption) *paramRegister {
+	return &paramRegister{
+		value: reflect.ValueOf(&value).Elem(),
+		opts:  opts,
+	}
+}
+
+// Provide registers a constructor without arguments. unlike Constructor, the shape of fn is checked at compile time.
+func Provide[T any](fn func() (T, error), opts ...registerOption) *constructorRegister {
+	return Constructor(fn, opts...)
+}
+
+// Provide1 registers a constructor with one argument.
+func Provide1[A, T any](fn func(A) (T, error), opts ...registerOption) *constructorRegister {
+	return Constructor(fn, opts...)
+}
+
+// Provide2 registers a constructor with two arguments.
+func Provide2[A, B, T any](fn func(A, B) (T, error), opts ...registerOption) *constructorRegister {
+	return Constructor(fn, opts...)
+}
+
+// Provide3 registers a constructor with three arguments.
+func Provide3[A, B, C, T any](fn func(A, B, C) (T, error), opts ...registerOption) *constructorRegister {
+	return Constructor(fn, opts...)
+}
+
+// Provide4 registers a constructor with four arguments.
+func Provide4[A, B, C, D, T any](fn func(A, B, C, D) (T, error), opts ...registerOption) *constructorRegister {
+	return Constructor(fn, opts...)
+}
+
+// Provide5 registers a constructor with five arguments.
+func Provide5[A, B, C, D, E, T any](fn func(A, B, C, D, E) (T, error), opts ...registerOption) *constructorRegister {
+	return Constructor(fn, opts...)
+}
+
+// Bind registers I as an alias of the registered T. T must implement I.
+//
+// e.g.
+// - Register(c, Provide(NewService), Bind[IService, *Service]())
+func Bind[I, T any](opts ...registerOption) *bindRegister {
+	return &bindRegister{
+		constructorRegister: Constructor(func(v T) I {
+			return any(v).(I)
+		}, opts...),
+		it: makeType[I](),
+		t:  makeType[T](),
+	}
+}
+
+type bindRegister struct {
+	*constructorRegister
+	it reflect.Type
+	t  reflect.Type
+}
+
+func (br *bindRegister) Keys() ([]dKey, error) {
+	if !br.t.AssignableTo(br.it) {
+		return nil, &notImplementsError{br.t, br.it}
+	}
+	return br.constructorRegister.Keys()
+}
+
+// Decorator registers a decorator that is applied each time the decorated dependency is built.
+// the first argument of fn is the dependency to decorate and the others are resolved from the container.
+// fn must return the decorated dependency and optionally an error.
+// decorators are applied in registration order to every binding of the type, or only to the binding tagged by Tag option.
+// the values of Param and Supply are decorated each time they are resolved.
+//
+// e.g.
+// - Register(c, Decorator(func(s *Service, l Logger) (*Service, error) { /* some code */ }))
+func Decorator(fn any, opts ...registerOption) *decoratorRegister {
+	return &decoratorRegister{
+		fn:   fn,
+		opts: opts,
+	}
+}
+
+type decoratorRegister struct {
+	fn   any
+	opts []registerOption
+}
+
+func (dr *decoratorRegister) Keys() ([]dKey, error) {
+	d, err := dr.decorator()
+	if err != nil {
+		return nil, err
+	}
+	return []dKey{{t: d.t, tag: d.tag}}, nil
+}
+
+func (dr *decoratorRegister) Deps() ([]*dependency, error) {
+	return []*dependency{{value: reflect.ValueOf(dr.fn)}}, nil
+}
+
+func (dr *decoratorRegister) Opts() []registerOption {
+	return dr.opts
+}
+
+func (dr *decoratorRegister) decorator() (*decorator, error) {
+	fv := reflect.ValueOf(dr.fn)
+	if fv.Kind() != reflect.Func {
+		return nil, &invalidDecoratorError{reflect.TypeOf(dr.fn)}
+	}
+	ft := fv.Type()
+	if ft.NumIn() < 1 || ft.NumOut() < 1 || ft.NumOut() > 2 || ft.Out(0) != ft.In(0) {
+		return nil, &invalidDecoratorError{ft}
+	}
+	if ft.NumOut() == 2 && ft.Out(1) != makeType[error]() {
+		return nil, &invalidDecoratorError{ft}
+	}
+	for i := 1; i < ft.NumIn(); i++ {
+		if ft.In(i) == ft.In(0) {
+			return nil, &cycleDependencyError{[]reflect.Type{ft.In(0), ft.In(0)}}
+		}
+	}
+	options := registerOptions{}
+	for _, opt := range dr.opts {
+		opt.applyRegisterOption(&options)
+	}
+	return &decorator{value: fv, t: ft.In(0), tag: options.Tag}, nil
+}
+
+// Intercept registers an interceptor that wraps the dependencies registered with Implements[I]() option.
+// interceptors are ordered by Priority option and can be restricted to a binding by Tag option.
+//
+// e.g.
+// - Register(c, Intercept(func(next Repository) Repository { return &loggingRepository{next} }))
+func Intercept[I any](fn func(next I) I, opts ...registerOption) *interceptorRegister {
+	return &interceptorRegister{
+		fn: func(v any) any {
+			return fn(v.(I))
+		},
+		t:    makeType[I](),
+		opts: opts,
+	}
+}
+
+type interceptorRegister struct {
+	fn   func(any) any
+	t    reflect.Type
+	opts []registerOption
+}
+
+func (ir *interceptorRegister) Keys() ([]dKey, error) {
+	if ir.t.Kind() != reflect.Interface {
+		return nil, &notInterfaceError{ir.t}
+	}
+	return []dKey{{t: ir.t}}, nil
+}
+
+func (ir *interceptorRegister) Deps() ([]*dependency, error) {
+	return []*dependency{{value: reflect.ValueOf(ir.fn)}}, nil
+}
+
+func (ir *interceptorRegister) Opts() []registerOption {
+	return ir.opts
+}
+
+// Generic registers a factory of the constructors of a generic type.
+// when a type that is not registered is resolved, factory is called with the type and must return the constructor
+// of the type, or nil if it does not build the type. the constructor is registered with opts,
+// so the instances are cached per type argument.
+//
+// e.g.
+//   - Register(c, Generic(func(t reflect.Type) any {
+//     switch t {
+//     case reflect.TypeOf(&Repo[User]{}):
+//     return NewRepo[User]
+//     case reflect.TypeOf(&Repo[Order]{}):
+//     return NewRepo[Order]
+//     }
+//     return nil
+//     }))
+func Generic(factory func(t reflect.Type) any, opts ...registerOption) *genericRegister {
+	return &genericRegister{
+		factory: factory,
+		opts:    opts,
+	}
+}
+
+type genericRegister struct {
+	factory func(t reflect.Type) any
+	opts    []registerOption
+}
+
+func (gr *genericRegister) Keys() ([]dKey, error) {
+	return nil, nil
+}
+
+func (gr *genericRegister) Deps() ([]*dependency, error) {
+	return nil, nil
+}
+
+func (gr *genericRegister) Opts() []registerOption {
+	return gr.opts
+}
+
+// All is a constructor argument that collects all bindings of T keyed by tag.
+//
+// e.g.
+// - Register(c, Constructor(func(repos All[Repository]) *Router { /* some code */ }))
+type All[T any] map[string]T
+
+func (All[T]) elemType() reflect.Type {
+	return makeType[T]()
+}
+
+type allParam interface {
+	elemType() reflect.Type
+}
+
+var allParamType = makeType[allParam]()
@@ -1,5 +1,11 @@
 package sticky
 
+import (
+	"context"
+	"errors"
+	"path"
+)
+
 type stickyContext interface {
 	Value(any) any
 }
@@ -9,8 +15,17 @@ func New(opts ...containerOption) Container {
 	return newContainer(opts...)
 }
 
+// FromContext returns the container saved in ctx by WithContext.
+func FromContext(ctx context.Context) (Container, bool) {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return nil, false
+	}
+	return c, true
+}
+
 // Register registers dependencies.
-func Register(ctx stickyContext, rters ...register) error {
+func Register(ctx stickyContext, rters ...Registration) error {
 	c, err := getContainer(ctx)
 	if err != nil {
 		return err
@@ -23,10 +38,37 @@ func Register(ctx stickyContext, rters ...register) error {
 	return nil
 }
 
+// Override registers dependencies replacing the registered ones with the same type and tag,
+// and disposes the cached instances so that dependants are rebuilt with the replacements.
+// Decorator, Intercept and Generic registrations are added instead of replacing the registered ones.
+// the returned function restores the replaced registrations, removes the added ones and disposes the instances built with them.
+func Override(ctx stickyContext, rters ...Registration) (func() error, error) {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var restores []func() error
+	restore := func() error {
+		var errs []error
+		for i := len(restores) - 1; i >= 0; i-- {
+			errs = append(errs, restores[i]())
+		}
+		return errors.Join(errs...)
+	}
+	for _, rter := range rters {
+		r, err := c.Override(rter)
+		if err != nil {
+			return nil, errors.Join(err, restore())
+		}
+		restores = append(restores, r)
+	}
+	return restore, nil
+}
+
 // Resolve resolves a dependency. it can use the following options.
 //
 // - Tag: can resolve a dependency by T's type and tag name
-func Resolve[T any](ctx stickyContext, opts ...resolveOption) (ret T, err error) {
+func Resolve[T any](ctx stickyContext, opts ...ResolveOption) (ret T, err error) {
 	var c *container
 	c, err = getContainer(ctx)
 	if err != nil {
@@ -43,7 +85,8 @@ func Resolve[T any](ctx stickyContext, opts ...resolveOption) (ret T, err error)
 	if err != nil {
 		return
 	}
-	ret = v.(T)
+	// v is nil if a nil interface value is registered.
+	ret, _ = v.(T)
 	return
 }
 
@@ -57,7 +100,7 @@ func Extract(ctx stickyContext, function any) error {
 }
 
 // Decorate allows to edit instance of generated dependencies.
-func Decorate[T any](ctx stickyContext, function func(T) (T, error), opts ...resolveOption) error {
+func Decorate[T any](ctx stickyContext, function func(T) (T, error), opts ...ResolveOption) error {
 	c, err := getContainer(ctx)
 	if err != nil {
 		return err
@@ -67,7 +110,8 @@ func Decorate[T any](ctx stickyContext, function func(T) (T, error), opts ...res
 		opt.applyResolveOption(&option)
 	}
 	var f func(any) (any, error) = func(v any) (any, error) {
-		return function(v.(T))
+		value, _ := v.(T)
+		return function(value)
 	}
 	t := makeType[T]()
 	key := dKey{t: t, tag: option.Tag}
@@ -82,3 +126,109 @@ func Validate(ctx stickyContext) error {
 	}
 	return c.Validate()
 }
+
+// Built reports whether the dependency has a cached instance. it can use the following options.
+//
+// - Tag: can find a dependency by T's type and tag name
+func Built[T any](ctx stickyContext, opts ...ResolveOption) (bool, error) {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return false, err
+	}
+	var option resolveOptions
+	for _, opt := range opts {
+		opt.applyResolveOption(&option)
+	}
+	return c.Built(dKey{t: makeType[T](), tag: option.Tag})
+}
+
+// Release returns v resolved from a Pooled dependency to the pool,
+// or disposes and discards the instance of the current goroutine for a ThreadLocal dependency.
+func Release[T any](ctx stickyContext, v T, opts ...ResolveOption) error {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return err
+	}
+	var option resolveOptions
+	for _, opt := range opts {
+		opt.applyResolveOption(&option)
+	}
+	return c.Release(dKey{t: makeType[T](), tag: option.Tag}, v)
+}
+
+// ResolveAsync resolves a dependency in another goroutine. the dependency is built once even if it is resolved
+// concurrently. the errors are returned by Future.Get.
+// the dependency is not built if ctx is done before the resolution starts.
+func ResolveAsync[T any](ctx context.Context, c stickyContext, opts ...ResolveOption) *Future[T] {
+	f := &Future[T]{}
+	_c, err := getContainer(c)
+	if err != nil {
+		f.f = &future{done: make(chan struct{}), err: err}
+		close(f.f.done)
+		return f
+	}
+	var option resolveOptions
+	for _, opt := range opts {
+		opt.applyResolveOption(&option)
+	}
+	f.f = _c.resolveAsync(ctx, dKey{t: makeType[T](), tag: option.Tag})
+	return f
+}
+
+// Explain returns a human-readable report of how T is resolved.
+// the report shows the resolution tree with the constructors and their source locations,
+// the dependencies that can not be resolved and the registered bindings that look like them.
+// the constructors are not called.
+func Explain[T any](ctx stickyContext, opts ...ResolveOption) (string, error) {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return "", err
+	}
+	var option resolveOptions
+	for _, opt := range opts {
+		opt.applyResolveOption(&option)
+	}
+	return c.Explain(dKey{t: makeType[T](), tag: option.Tag}), nil
+}
+
+// ResolveAll resolves all bindings of T keyed by tag.
+func ResolveAll[T any](ctx stickyContext) (map[string]T, error) {
+	return resolveAll[T](ctx, nil)
+}
+
+// ResolveMatching resolves the bindings of T whose tag matches pattern, keyed by tag.
+// the pattern syntax is the same as path.Match. e.g. "repo.*"
+func ResolveMatching[T any](ctx stickyContext, pattern string) (map[string]T, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return resolveAll[T](ctx, func(tag string) bool {
+		ok, _ := path.Match(pattern, tag)
+		return ok
+	})
+}
+
+func resolveAll[T any](ctx stickyContext, match func(string) bool) (map[string]T, error) {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values, err := c.ResolveAll(makeType[T](), match)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]T, len(values))
+	for tag, v := range values {
+		ret[tag], _ = v.(T)
+	}
+	return ret, nil
+}
+
+// Bindings returns the registrations of the container and its parents in registration order, starting from the container.
+func Bindings(ctx stickyContext) ([]Binding, error) {
+	c, err := getContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.Bindings(), nil
+}
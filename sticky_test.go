@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -227,6 +228,55 @@ func TestE2ESuccess(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, &A{100}, p4)
 	})
+
+	t.Run("provide", func(t *testing.T) {
+		type A struct{ string }
+		type B struct{ A }
+		c := New()
+		require.NoError(t, Register(c,
+			Provide(func() (A, error) { return A{"a"}, nil }),
+			Provide1(func(a A) (*B, error) { return &B{a}, nil }, Tag("b")),
+		))
+		v, err := Resolve[*B](c, Tag("b"))
+		require.NoError(t, err)
+		assert.Equal(t, &B{A{"a"}}, v)
+	})
+
+	t.Run("supply", func(t *testing.T) {
+		var buf bytes.Buffer
+		c := New()
+		require.NoError(t, Register(c,
+			Supply[io.Writer](&buf, Tag("writer")),
+			Supply(100),
+		))
+		w, err := Resolve[io.Writer](c, Tag("writer"))
+		require.NoError(t, err)
+		assert.Same(t, &buf, w)
+		n, err := Resolve[int](c)
+		require.NoError(t, err)
+		assert.Equal(t, 100, n)
+	})
+
+	t.Run("supply nil interface", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Supply[io.Writer](nil)))
+		w, err := Resolve[io.Writer](c)
+		require.NoError(t, err)
+		assert.Nil(t, w)
+	})
+
+	t.Run("bind", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() *bytes.Buffer { return bytes.NewBufferString("foo") }),
+			Bind[io.Reader, *bytes.Buffer](),
+		))
+		r, err := Resolve[io.Reader](c)
+		require.NoError(t, err)
+		b, err := Resolve[*bytes.Buffer](c)
+		require.NoError(t, err)
+		assert.Same(t, b, r)
+	})
 }
 
 func TestE2EFailure(t *testing.T) {
@@ -275,6 +325,56 @@ func TestE2EFailure(t *testing.T) {
 		err := Register(c, Constructor(func(c C) D { return D{} }))
 		assert.True(t, errors.As(err, &e))
 	})
+
+	t.Run("cycle dependency through all", func(t *testing.T) {
+		type A struct{}
+
+		c := New()
+		var e *cycleDependencyError
+		err := Register(c, Constructor(func(All[*A]) *A { return &A{} }, Tag("all")))
+		assert.True(t, errors.As(err, &e))
+	})
+
+	t.Run("cycle dependency through variadic", func(t *testing.T) {
+		type A struct{}
+
+		c := New()
+		var e *cycleDependencyError
+		err := Register(c, Constructor(func(...*A) *A { return &A{} }, Tag("variadic")))
+		assert.True(t, errors.As(err, &e))
+	})
+
+	t.Run("cycle dependency through future", func(t *testing.T) {
+		type A struct{}
+
+		c := New()
+		var e *cycleDependencyError
+		err := Register(c, Constructor(func(Future[*A]) *A { return &A{} }))
+		assert.True(t, errors.As(err, &e))
+	})
+
+	t.Run("cycle dependency through named", func(t *testing.T) {
+		type A struct{}
+
+		c := New()
+		var e *cycleDependencyError
+		err := Register(c, Constructor(func(Named[*A, testEndpoint]) *A { return &A{} }, Qualified[testEndpoint]()))
+		assert.True(t, errors.As(err, &e))
+
+		c = New()
+		require.NoError(t, Register(c, Constructor(func(Named[*A, testEndpoint]) *A { return &A{} })))
+	})
+
+	t.Run("not implements", func(t *testing.T) {
+		c := New()
+
+		type A struct{}
+		var e *notImplementsError
+		err := Register(c, Bind[io.Reader, *A]())
+		assert.True(t, errors.As(err, &e))
+		err = Register(c, Constructor(func() *A { return &A{} }, Implements[io.Reader]()))
+		assert.True(t, errors.As(err, &e))
+	})
 }
 
 func TestWithContext(t *testing.T) {
@@ -290,3 +390,512 @@ func TestWithContext(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, &A{"a"}, v)
 }
+
+type testRepository interface{ Find() string }
+
+type testMemoryRepository struct{ value string }
+
+func (r *testMemoryRepository) Find() string { return r.value }
+
+type testWrappedRepository struct {
+	next   testRepository
+	prefix string
+}
+
+func (r *testWrappedRepository) Find() string { return r.prefix + r.next.Find() }
+
+func TestIntercept(t *testing.T) {
+	t.Parallel()
+
+	wrap := func(prefix string) func(testRepository) testRepository {
+		return func(next testRepository) testRepository {
+			return &testWrappedRepository{next, prefix}
+		}
+	}
+
+	t.Run("priority", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Intercept(wrap("high:"), Priority(10)),
+			Intercept(wrap("low:"), Priority(-1)),
+			Intercept(wrap("default:")),
+			Constructor(func() *testMemoryRepository {
+				return &testMemoryRepository{"value"}
+			}, Implements[testRepository]()),
+		))
+		r, err := Resolve[testRepository](c)
+		require.NoError(t, err)
+		assert.Equal(t, "high:default:low:value", r.Find())
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Intercept(wrap("api:"), Tag("api")),
+			Constructor(func() *testMemoryRepository {
+				return &testMemoryRepository{"memory"}
+			}, Implements[testRepository](), Tag("memory")),
+			Constructor(func() *testMemoryRepository {
+				return &testMemoryRepository{"api"}
+			}, Implements[testRepository](), Tag("api")),
+		))
+		r, err := Resolve[testRepository](c, Tag("memory"))
+		require.NoError(t, err)
+		assert.Equal(t, "memory", r.Find())
+		r, err = Resolve[testRepository](c, Tag("api"))
+		require.NoError(t, err)
+		assert.Equal(t, "api:api", r.Find())
+	})
+
+	t.Run("without Implements", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Intercept(wrap("wrapped:")),
+			Constructor(func() testRepository {
+				return &testMemoryRepository{"value"}
+			}),
+		))
+		r, err := Resolve[testRepository](c)
+		require.NoError(t, err)
+		assert.Equal(t, "value", r.Find())
+	})
+
+	t.Run("not interface", func(t *testing.T) {
+		c := New()
+		var e *notInterfaceError
+		err := Register(c, Intercept(func(next *testMemoryRepository) *testMemoryRepository { return next }))
+		assert.True(t, errors.As(err, &e))
+	})
+}
+
+func TestOverride(t *testing.T) {
+	type A struct{ string }
+	type B struct{ A }
+
+	c := New()
+	require.NoError(t, Register(c,
+		Constructor(func() A { return A{"real"} }),
+		Constructor(func(a A) *B { return &B{a} }),
+	))
+	b, err := Resolve[*B](c)
+	require.NoError(t, err)
+	assert.Equal(t, "real", b.string)
+
+	restore, err := Override(c, Constructor(func() A { return A{"fake"} }))
+	require.NoError(t, err)
+	built, err := Built[*B](c)
+	require.NoError(t, err)
+	assert.False(t, built)
+	b, err = Resolve[*B](c)
+	require.NoError(t, err)
+	assert.Equal(t, "fake", b.string)
+
+	require.NoError(t, restore())
+	b, err = Resolve[*B](c)
+	require.NoError(t, err)
+	assert.Equal(t, "real", b.string)
+
+	t.Run("dispose", func(t *testing.T) {
+		type C struct{ *testCloser }
+
+		var closed []string
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() *C { return &C{&testCloser{name: "real", closed: &closed}} })))
+		_, err := Resolve[*C](c)
+		require.NoError(t, err)
+
+		restore, err := Override(c, Constructor(func() *C { return &C{&testCloser{name: "fake", closed: &closed}} }))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"real"}, closed)
+		_, err = Resolve[*C](c)
+		require.NoError(t, err)
+		require.NoError(t, restore())
+		assert.Equal(t, []string{"real", "fake"}, closed)
+	})
+
+	t.Run("decorator, interceptor and generic", func(t *testing.T) {
+		type upperRepository struct{ testRepository }
+
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() A { return A{"real"} }),
+			Constructor(func() *testMemoryRepository { return &testMemoryRepository{"memory"} }, Implements[testRepository]()),
+			Constructor(func() *testStore { return &testStore{} }),
+		))
+		restore, err := Override(c,
+			Decorator(func(a A) A { return A{"decorated " + a.string} }),
+			Intercept(func(next testRepository) testRepository { return &upperRepository{next} }),
+			Generic(func(t reflect.Type) any {
+				if t == reflect.TypeOf(&testRepo[testUser]{}) {
+					return newTestRepo[testUser]
+				}
+				return nil
+			}),
+		)
+		require.NoError(t, err)
+		a, err := Resolve[A](c)
+		require.NoError(t, err)
+		assert.Equal(t, "decorated real", a.string)
+		repo, err := Resolve[testRepository](c)
+		require.NoError(t, err)
+		assert.IsType(t, &upperRepository{}, repo)
+		_, err = Resolve[*testRepo[testUser]](c)
+		require.NoError(t, err)
+
+		require.NoError(t, restore())
+		a, err = Resolve[A](c)
+		require.NoError(t, err)
+		assert.Equal(t, "real", a.string)
+		repo, err = Resolve[testRepository](c)
+		require.NoError(t, err)
+		assert.IsType(t, &testMemoryRepository{}, repo)
+		_, err = Resolve[*testRepo[testUser]](c)
+		assert.Error(t, err)
+	})
+}
+
+func TestResolveAll(t *testing.T) {
+	t.Parallel()
+
+	newRepo := func(value string) func() *testMemoryRepository {
+		return func() *testMemoryRepository { return &testMemoryRepository{value} }
+	}
+	c := New()
+	require.NoError(t, Register(c,
+		Constructor(newRepo("memory"), Implements[testRepository](), Tag("repo.memory")),
+		Constructor(newRepo("api"), Implements[testRepository](), Tag("repo.api")),
+		Constructor(newRepo("cache"), Implements[testRepository](), Tag("cache")),
+	))
+
+	find := func(repos map[string]testRepository) map[string]string {
+		ret := make(map[string]string, len(repos))
+		for tag, r := range repos {
+			ret[tag] = r.Find()
+		}
+		return ret
+	}
+
+	t.Run("all", func(t *testing.T) {
+		repos, err := ResolveAll[testRepository](c)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"repo.memory": "memory", "repo.api": "api", "cache": "cache"}, find(repos))
+	})
+
+	t.Run("matching", func(t *testing.T) {
+		repos, err := ResolveMatching[testRepository](c, "repo.*")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"repo.memory": "memory", "repo.api": "api"}, find(repos))
+
+		_, err = ResolveMatching[testRepository](c, "[")
+		assert.Error(t, err)
+	})
+
+	t.Run("map injection", func(t *testing.T) {
+		type Router struct{ repos map[string]testRepository }
+		scope := c.Scope()
+		require.NoError(t, Register(scope,
+			Constructor(newRepo("scoped"), Implements[testRepository](), Tag("cache")),
+			Constructor(func(repos All[testRepository]) *Router { return &Router{repos} }),
+		))
+		r, err := Resolve[*Router](scope)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"repo.memory": "memory", "repo.api": "api", "cache": "scoped"}, find(r.repos))
+	})
+}
+
+func TestContext(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	t.Run("from context", func(t *testing.T) {
+		c := New()
+		got, ok := FromContext(c.WithContext(context.Background()))
+		assert.True(t, ok)
+		assert.Same(t, c, got)
+		got, ok = FromContext(c)
+		assert.True(t, ok)
+		assert.Same(t, c, got)
+		_, ok = FromContext(context.Background())
+		assert.False(t, ok)
+	})
+
+	t.Run("base context", func(t *testing.T) {
+		base, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+		c := New(BaseContext(base))
+		assert.Equal(t, "value", c.Value(ctxKey{}))
+		assert.Equal(t, "value", c.Scope().Value(ctxKey{}))
+		assert.Nil(t, New().Value(ctxKey{}))
+
+		cancel()
+		<-c.Done()
+		assert.Equal(t, context.Canceled, c.Err())
+	})
+
+	t.Run("derived context", func(t *testing.T) {
+		type A struct{}
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() A { return A{} })))
+		ctx := context.WithValue(c, ctxKey{}, "value")
+		_, err := Resolve[A](ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("foreign value", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), defaultKey, "foreign")
+		_, err := Resolve[string](ctx)
+		assert.Error(t, err)
+		_, ok := FromContext(ctx)
+		assert.False(t, ok)
+	})
+}
+
+func TestParent(t *testing.T) {
+	t.Parallel()
+
+	type Config struct{ name string }
+	type DB struct{ *Config }
+	type Service struct{ *DB }
+
+	base := New()
+	require.NoError(t, Register(base,
+		Constructor(func() *Config { return &Config{"platform"} }),
+		Constructor(func(c *Config) *DB { return &DB{c} }),
+	))
+	tenant := New(Parent(base))
+	require.NoError(t, Register(tenant,
+		Constructor(func() *Config { return &Config{"tenant"} }),
+		Constructor(func(db *DB) *Service { return &Service{db} }),
+	))
+	require.NoError(t, Validate(tenant))
+
+	config, err := Resolve[*Config](tenant)
+	require.NoError(t, err)
+	assert.Equal(t, "tenant", config.name)
+
+	// DB is built and cached in the parent.
+	db, err := Resolve[*DB](tenant)
+	require.NoError(t, err)
+	assert.Equal(t, "platform", db.name)
+	baseDB, err := Resolve[*DB](base)
+	require.NoError(t, err)
+	assert.Same(t, db, baseDB)
+
+	_, err = Resolve[*Service](base)
+	assert.Error(t, err)
+
+	bindings, err := Bindings(tenant)
+	require.NoError(t, err)
+	require.Len(t, bindings, 4)
+	assert.Equal(t, Binding{Key: Key{Type: reflect.TypeOf(&Config{})}}, bindings[0])
+	assert.Equal(t, []Key{{Type: reflect.TypeOf(&DB{})}}, bindings[1].Dependencies)
+	assert.Equal(t, Binding{Key: Key{Type: reflect.TypeOf(&Config{})}, Level: 1, Shadowed: true}, bindings[2])
+	assert.Equal(t, 1, bindings[3].Level)
+	assert.False(t, bindings[3].Shadowed)
+}
+
+type testEntity interface{ entityName() string }
+
+type testUser struct{}
+
+func (testUser) entityName() string { return "user" }
+
+type testOrder struct{}
+
+func (testOrder) entityName() string { return "order" }
+
+type testStore struct{}
+
+type testRepo[T testEntity] struct {
+	db *testStore
+}
+
+func newTestRepo[T testEntity](db *testStore) *testRepo[T] {
+	return &testRepo[T]{db}
+}
+
+func TestGeneric(t *testing.T) {
+	t.Parallel()
+
+	repos := func(t reflect.Type) any {
+		switch t {
+		case reflect.TypeOf(&testRepo[testUser]{}):
+			return newTestRepo[testUser]
+		case reflect.TypeOf(&testRepo[testOrder]{}):
+			return newTestRepo[testOrder]
+		}
+		return nil
+	}
+
+	t.Run("instantiate", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() *testStore { return &testStore{} }),
+			Generic(repos),
+		))
+		users, err := Resolve[*testRepo[testUser]](c)
+		require.NoError(t, err)
+		usersAgain, err := Resolve[*testRepo[testUser]](c)
+		require.NoError(t, err)
+		assert.Same(t, users, usersAgain)
+		orders, err := Resolve[*testRepo[testOrder]](c)
+		require.NoError(t, err)
+		assert.Same(t, users.db, orders.db)
+
+		_, err = Resolve[*testRepo[testEntity]](c)
+		assert.Error(t, err)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		type Service struct{ *testRepo[testUser] }
+
+		c := New()
+		require.NoError(t, Register(c,
+			Generic(repos),
+			Constructor(func(r *testRepo[testUser]) *Service { return &Service{r} }),
+		))
+		err := Validate(c)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "testStore")
+		built, err := Built[*testRepo[testUser]](c)
+		assert.Error(t, err)
+		assert.False(t, built)
+
+		require.NoError(t, Register(c, Constructor(func() *testStore { return &testStore{} })))
+		assert.NoError(t, Validate(c))
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		type Service struct{ *testRepo[testUser] }
+
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() *testStore { return &testStore{} }),
+			Generic(repos),
+			Constructor(func(r *testRepo[testUser]) *Service { return &Service{r} }),
+		))
+		for i := 0; i < 3; i++ {
+			require.NoError(t, Validate(c))
+		}
+		snapshot := c.Snapshot()
+		_, err := Resolve[*testRepo[testUser]](snapshot)
+		require.NoError(t, err)
+		assert.Empty(t, c.(*container).generics[0].keys, "the instantiations of the clones are not recorded in the original")
+		_, ok := c.(*container).local(dKey{t: reflect.TypeOf(&testRepo[testUser]{})})
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid constructor", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Generic(func(reflect.Type) any {
+			return func() *testStore { return &testStore{} }
+		})))
+		_, err := Resolve[*testRepo[testUser]](c)
+		assert.Error(t, err)
+	})
+}
+
+type testRoute interface{ Path() string }
+
+type testPathRoute string
+
+func (r testPathRoute) Path() string { return string(r) }
+
+type testRouter struct{ routes []testRoute }
+
+func newTestRouter(routes ...testRoute) *testRouter {
+	return &testRouter{routes}
+}
+
+func TestVariadic(t *testing.T) {
+	t.Parallel()
+
+	paths := func(r *testRouter) []string {
+		var ps []string
+		for _, route := range r.routes {
+			ps = append(ps, route.Path())
+		}
+		return ps
+	}
+
+	t.Run("all bindings", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() testRoute { return testPathRoute("/users") }, Tag("users")),
+			Constructor(func() testRoute { return testPathRoute("/orders") }, Tag("orders")),
+			Constructor(newTestRouter),
+		))
+		require.NoError(t, Validate(c))
+		r, err := Resolve[*testRouter](c)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/users", "/orders"}, paths(r))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(newTestRouter)))
+		r, err := Resolve[*testRouter](c)
+		require.NoError(t, err)
+		assert.Empty(t, r.routes)
+	})
+
+	t.Run("group", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c,
+			Constructor(func() testRoute { return testPathRoute("/internal") }),
+			Constructor(func() testPathRoute { return "/health" }, Group("public")),
+			Constructor(func() testRoute { return testPathRoute("/users") }, Tag("users"), Group("public")),
+			Constructor(newTestRouter, FromGroup("public")),
+		))
+		r, err := Resolve[*testRouter](c)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/health", "/users"}, paths(r))
+	})
+
+	t.Run("extract", func(t *testing.T) {
+		c := New()
+		require.NoError(t, Register(c, Constructor(func() testRoute { return testPathRoute("/users") })))
+		var got []testRoute
+		require.NoError(t, Extract(c, func(routes ...testRoute) { got = routes }))
+		assert.Len(t, got, 1)
+	})
+}
+
+type testEndpoint struct{}
+
+type testPrimary struct{}
+
+func (testPrimary) Tag() string { return "primary" }
+
+func TestNamed(t *testing.T) {
+	t.Parallel()
+
+	type Client struct{ endpoint string }
+
+	c := New()
+	require.NoError(t, Register(c,
+		Supply("http://localhost", Qualified[testEndpoint]()),
+		Param("http://replica", "primary"),
+		Constructor(func(endpoint Named[string, testEndpoint]) *Client { return &Client{endpoint.Value} }),
+	))
+	require.NoError(t, Validate(c))
+
+	client, err := Resolve[*Client](c)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost", client.endpoint)
+
+	endpoint, err := Resolve[Named[string, testEndpoint]](c)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost", endpoint.Value)
+	tagged, err := Resolve[string](c, Tag(QualifierTag[testEndpoint]()))
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost", tagged)
+	assert.Equal(t, "github.com/ssstoyama/sticky.testEndpoint", QualifierTag[testEndpoint]())
+
+	primary, err := Resolve[Named[string, testPrimary]](c)
+	require.NoError(t, err)
+	assert.Equal(t, "http://replica", primary.Value)
+
+	_, err = Resolve[Named[int, testEndpoint]](c)
+	assert.EqualError(t, err, "not found register: type=int, tag=github.com/ssstoyama/sticky.testEndpoint")
+}
@@ -0,0 +1,144 @@
+// Package stickyhttp provides net/http middleware that gives every request its own sticky scope.
+package stickyhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/ssstoyama/sticky"
+)
+
+// Option configures Middleware and Handler.
+type Option func(*options)
+
+type options struct {
+	registers    []func(r *http.Request, scope sticky.Container) error
+	errorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{errorHandler: defaultErrorHandler}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Register option registers request-derived values in the request scope.
+//
+// e.g.
+//   - Register(func(r *http.Request, scope sticky.Container) error {
+//     return sticky.Register(scope, sticky.Param(r.Header.Get("X-User-ID"), "user_id"))
+//     })
+func Register(fn func(r *http.Request, scope sticky.Container) error) Option {
+	return func(o *options) {
+		o.registers = append(o.registers, fn)
+	}
+}
+
+// ErrorHandler option replaces the handler called when the scope can not be created
+// or the dependencies of a Handler can not be resolved. the default responds 500 Internal Server Error.
+func ErrorHandler(fn func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return func(o *options) {
+		o.errorHandler = fn
+	}
+}
+
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, _ error) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// Middleware creates a scope of c for each request and attaches it to the request context.
+// *http.Request, http.ResponseWriter and context.Context of the request are registered in the scope.
+// the scope is closed after the handler returns. errors of closing the scope are ignored.
+func Middleware(c sticky.Container, opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := c.Scope()
+			defer scope.Close(context.WithoutCancel(r.Context()))
+			r = r.WithContext(scope.WithContext(r.Context()))
+			err := sticky.Register(scope,
+				sticky.Param(r, ""),
+				sticky.Supply[http.ResponseWriter](w),
+				sticky.Supply[context.Context](r.Context()),
+			)
+			for _, register := range o.registers {
+				if err != nil {
+					break
+				}
+				err = register(r, scope)
+			}
+			if err != nil {
+				o.errorHandler(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Handler creates http.Handler that calls fn with the dependencies resolved from the request scope.
+// fn must return nothing or an error. the error is passed to the ErrorHandler option.
+// the handler must be served behind Middleware.
+//
+// e.g.
+//   - Handler(func(w http.ResponseWriter, r *http.Request, s *Service) error { /* some code */ })
+func Handler(fn any, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumOut() > 1 || (ft.NumOut() == 1 && ft.Out(0) != errorType) {
+		panic(fmt.Sprintf("stickyhttp: invalid handler. must be function returning nothing or error. got=%s", ft))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var fnErr error
+		// Extract ignores the results, so the error is captured by the wrapper.
+		wrapper := reflect.MakeFunc(reflect.FuncOf(inTypes(ft), nil, ft.IsVariadic()), func(args []reflect.Value) []reflect.Value {
+			var results []reflect.Value
+			if ft.IsVariadic() {
+				results = fv.CallSlice(args)
+			} else {
+				results = fv.Call(args)
+			}
+			if len(results) == 1 && !results[0].IsNil() {
+				fnErr = results[0].Interface().(error)
+			}
+			return nil
+		})
+		if err := sticky.Extract(r.Context(), wrapper.Interface()); err != nil {
+			o.errorHandler(w, r, err)
+			return
+		}
+		if fnErr != nil {
+			o.errorHandler(w, r, fnErr)
+		}
+	})
+}
+
+func inTypes(ft reflect.Type) []reflect.Type {
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+	return in
+}
+
+// HealthHandler creates http.Handler that serves the health report of c as JSON.
+// it responds 200 OK if all the checks pass, and 503 Service Unavailable otherwise.
+// it can be used for liveness and readiness probes.
+func HealthHandler(c sticky.Container) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Health(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,115 @@
+package stickyhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ssstoyama/sticky"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greeter struct{ prefix string }
+
+type requestGreeter struct {
+	*greeter
+	name string
+}
+
+func TestMiddleware(t *testing.T) {
+	c := sticky.New()
+	require.NoError(t, sticky.Register(c,
+		sticky.Constructor(func() *greeter { return &greeter{"hello, "} }),
+	))
+
+	var built int
+	h := Middleware(c, Register(func(r *http.Request, scope sticky.Container) error {
+		return sticky.Register(scope, sticky.Constructor(func(g *greeter, r *http.Request) *requestGreeter {
+			built++
+			return &requestGreeter{g, r.URL.Query().Get("name")}
+		}))
+	}))(Handler(func(w http.ResponseWriter, g *requestGreeter) {
+		io.WriteString(w, g.prefix+g.name)
+	}))
+
+	for _, name := range []string{"giorno", "bruno"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name="+name, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hello, "+name, rec.Body.String())
+	}
+	assert.Equal(t, 2, built)
+
+	_, err := sticky.Resolve[*requestGreeter](c)
+	assert.Error(t, err, "request scoped bindings must not leak into the parent")
+}
+
+func TestHandlerError(t *testing.T) {
+	c := sticky.New()
+
+	var got error
+	onError := ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		got = err
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		h := Middleware(c)(Handler(func(r *http.Request) error {
+			return errors.New("dummy error")
+		}, onError))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+		assert.Equal(t, errors.New("dummy error"), got)
+	})
+
+	t.Run("not resolved", func(t *testing.T) {
+		h := Middleware(c)(Handler(func(g *greeter) {}, onError))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+	})
+
+	t.Run("invalid handler", func(t *testing.T) {
+		assert.Panics(t, func() { Handler(func() int { return 0 }) })
+	})
+}
+
+type checker struct{ err error }
+
+func (c *checker) Check(context.Context) error { return c.err }
+
+func TestHealthHandler(t *testing.T) {
+	type DB struct{ *checker }
+	type Cache struct{ *checker }
+
+	c := sticky.New()
+	require.NoError(t, sticky.Register(c,
+		sticky.Constructor(func() *DB { return &DB{&checker{}} }),
+		sticky.Constructor(func() *Cache { return &Cache{&checker{errors.New("cache down")}} }),
+	))
+	_, err := sticky.Resolve[*DB](c)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	HealthHandler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	_, err = sticky.Resolve[*Cache](c)
+	require.NoError(t, err)
+	rec = httptest.NewRecorder()
+	HealthHandler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var report sticky.HealthReport
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.False(t, report.Healthy)
+	require.Len(t, report.Checks, 2)
+	assert.True(t, report.Checks[0].Healthy)
+	assert.Equal(t, "cache down", report.Checks[1].Error)
+}
@@ -0,0 +1,15 @@
+// Command stickylint reports misuses of sticky.
+//
+// usage:
+//
+//	go vet -vettool=$(which stickylint) ./...
+package main
+
+import (
+	"github.com/ssstoyama/sticky/stickylint"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(stickylint.Analyzer)
+}
@@ -0,0 +1,298 @@
+// Package stickylint defines an Analyzer that reports misuses of sticky
+// that would otherwise only show up at runtime.
+//
+// it reports
+//   - Constructor called with a value that is not a factory function
+//   - Implements[I]() used with a constructor whose result does not implement I
+//   - Resolve, Decorate and Extract of a type that is never registered in the package
+//
+// the resolutions are not checked in the packages that register dependencies
+// with Generic, LoadManifest or a Parent container, since they can not be determined statically.
+package stickylint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const stickyPath = "github.com/ssstoyama/sticky"
+
+// Analyzer reports misuses of sticky.
+var Analyzer = &analysis.Analyzer{
+	Name:     "stickylint",
+	Doc:      "report misuses of sticky registrations and resolutions",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var errorIface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// registration is a dependency registered in the package.
+type registration struct {
+	t   types.Type
+	tag string
+	// constTag is false when the tag can not be determined statically.
+	constTag bool
+}
+
+type checker struct {
+	pass          *analysis.Pass
+	registrations []registration
+	// registered is true if the package calls sticky.Register.
+	registered bool
+	// dynamic is true if the package calls sticky.Generic, sticky.LoadManifest or sticky.Parent.
+	dynamic bool
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	c := &checker{pass: pass}
+
+	var uses []*ast.CallExpr
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		switch c.stickyFunc(call) {
+		case "Register":
+			c.registered = true
+		case "Generic", "LoadManifest", "Parent":
+			c.dynamic = true
+		case "Constructor", "Provide", "Provide1", "Provide2", "Provide3", "Provide4", "Provide5":
+			c.constructor(call)
+		case "Param":
+			c.param(call)
+		case "Supply":
+			c.add(c.typeArg(call, 0), call.Args[1:])
+		case "Bind":
+			c.add(c.typeArg(call, 0), call.Args)
+		case "Resolve", "Decorate", "Extract":
+			uses = append(uses, call)
+		}
+	})
+
+	if !c.registered || c.dynamic {
+		return nil, nil
+	}
+	for _, call := range uses {
+		switch c.stickyFunc(call) {
+		case "Resolve", "Decorate":
+			c.checkResolvable(call, c.typeArg(call, 0), c.tagOption(call.Args[1:]))
+		case "Extract":
+			if len(call.Args) < 2 {
+				continue
+			}
+			sig, ok := pass.TypesInfo.TypeOf(call.Args[1]).Underlying().(*types.Signature)
+			if !ok {
+				pass.Reportf(call.Args[1].Pos(), "sticky.Extract: invalid value. must be function")
+				continue
+			}
+			for i := 0; i < sig.Params().Len(); i++ {
+				// the variadic parameter resolves all the bindings, which may be none.
+				if sig.Variadic() && i == sig.Params().Len()-1 {
+					continue
+				}
+				c.checkResolvable(call, sig.Params().At(i).Type(), &registration{constTag: true})
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (c *checker) constructor(call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+	fn := call.Args[0]
+	t := c.pass.TypesInfo.TypeOf(fn)
+	sig, ok := t.Underlying().(*types.Signature)
+	if !ok {
+		c.pass.Reportf(fn.Pos(), "sticky.Constructor: invalid constructor. must be factory function. got=%s", t)
+		return
+	}
+	if sig.Results().Len() == 0 {
+		c.pass.Reportf(fn.Pos(), "sticky.Constructor: invalid constructor. must return a value")
+		return
+	}
+	opts := call.Args[1:]
+	if it := c.implementsOption(opts); it != nil {
+		rt := sig.Results().At(0).Type()
+		if !types.AssignableTo(rt, it) {
+			c.pass.Reportf(fn.Pos(), "sticky.Implements: %s does not implement %s", rt, it)
+		}
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		c.add(sig.Results().At(i).Type(), opts)
+	}
+}
+
+func (c *checker) param(call *ast.CallExpr) {
+	if len(call.Args) < 2 {
+		return
+	}
+	r := registration{t: types.Default(c.pass.TypesInfo.TypeOf(call.Args[0]))}
+	if v := c.pass.TypesInfo.Types[call.Args[1]].Value; v != nil && v.Kind() == constant.String {
+		r.tag, r.constTag = constant.StringVal(v), true
+	}
+	c.registrations = append(c.registrations, r)
+}
+
+// add registers t with the tag and Implements options in opts.
+func (c *checker) add(t types.Type, opts []ast.Expr) {
+	if t == nil || types.Implements(t, errorIface) {
+		return
+	}
+	r := registration{t: t, constTag: true}
+	if tag := c.tagOption(opts); tag != nil {
+		r.tag, r.constTag = tag.tag, tag.constTag
+	}
+	if it := c.implementsOption(opts); it != nil {
+		r.t = it
+	}
+	c.registrations = append(c.registrations, r)
+}
+
+// checkResolvable reports call if t is never registered with the tag.
+func (c *checker) checkResolvable(call *ast.CallExpr, t types.Type, tag *registration) {
+	if t == nil {
+		return
+	}
+	if tag == nil {
+		tag = &registration{constTag: true}
+	}
+	// context.Context falls back to the container, and All[T] resolves all the bindings of T, which may be none.
+	if isContext(t) || stickyTypeArgs(t, "All") != nil {
+		return
+	}
+	// Future[T] resolves T.
+	if args := stickyTypeArgs(t, "Future"); args != nil {
+		t = args.At(0)
+	}
+	// Named[T, Q] resolves T with the tag of Q.
+	if elem := namedElem(t); elem != nil {
+		t, tag = elem, &registration{}
+	}
+	found := false
+	for _, r := range c.registrations {
+		if !types.Identical(r.t, t) {
+			continue
+		}
+		if !r.constTag || !tag.constTag || r.tag == tag.tag {
+			return
+		}
+		found = true
+	}
+	name := c.stickyFunc(call)
+	if found {
+		c.pass.Reportf(call.Pos(), "sticky.%s: %s is not registered with tag %q", name, t, tag.tag)
+		return
+	}
+	c.pass.Reportf(call.Pos(), "sticky.%s: %s is never registered", name, t)
+}
+
+// tagOption returns the tag given by the Tag option in opts. nil if there is no Tag option.
+func (c *checker) tagOption(opts []ast.Expr) *registration {
+	for _, opt := range opts {
+		call, ok := opt.(*ast.CallExpr)
+		if !ok {
+			return &registration{}
+		}
+		if c.stickyFunc(call) == "Qualified" {
+			return &registration{}
+		}
+		if c.stickyFunc(call) != "Tag" || len(call.Args) != 1 {
+			continue
+		}
+		v := c.pass.TypesInfo.Types[call.Args[0]].Value
+		if v == nil || v.Kind() != constant.String {
+			return &registration{}
+		}
+		return &registration{tag: constant.StringVal(v), constTag: true}
+	}
+	return nil
+}
+
+// namedElem returns T of sticky.Named[T, Q]. nil if t is not Named.
+func namedElem(t types.Type) types.Type {
+	args := stickyTypeArgs(t, "Named")
+	if args == nil || args.Len() != 2 {
+		return nil
+	}
+	return args.At(0)
+}
+
+// stickyTypeArgs returns the type arguments of t if t is the generic type name of sticky. nil if it is not.
+func stickyTypeArgs(t types.Type, name string) *types.TypeList {
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeArgs().Len() == 0 {
+		return nil
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != stickyPath || obj.Name() != name {
+		return nil
+	}
+	return named.TypeArgs()
+}
+
+// isContext reports whether t is context.Context.
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// implementsOption returns the type argument of the Implements option in opts.
+func (c *checker) implementsOption(opts []ast.Expr) types.Type {
+	for _, opt := range opts {
+		call, ok := opt.(*ast.CallExpr)
+		if ok && c.stickyFunc(call) == "Implements" {
+			return c.typeArg(call, 0)
+		}
+	}
+	return nil
+}
+
+// stickyFunc returns the name of the sticky function called by call.
+func (c *checker) stickyFunc(call *ast.CallExpr) string {
+	id := funcIdent(call)
+	if id == nil {
+		return ""
+	}
+	fn, ok := c.pass.TypesInfo.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != stickyPath {
+		return ""
+	}
+	return fn.Name()
+}
+
+// typeArg returns i-th type argument of the generic function called by call.
+func (c *checker) typeArg(call *ast.CallExpr, i int) types.Type {
+	inst, ok := c.pass.TypesInfo.Instances[funcIdent(call)]
+	if !ok || inst.TypeArgs.Len() <= i {
+		return nil
+	}
+	return inst.TypeArgs.At(i)
+}
+
+func funcIdent(call *ast.CallExpr) *ast.Ident {
+	fun := call.Fun
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	}
+	return nil
+}
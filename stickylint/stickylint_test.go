@@ -0,0 +1,12 @@
+package stickylint_test
+
+import (
+	"testing"
+
+	"github.com/ssstoyama/sticky/stickylint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), stickylint.Analyzer, "a", "generic", "manifest", "parent")
+}
@@ -0,0 +1,52 @@
+package a
+
+import (
+	"context"
+	"io"
+
+	"github.com/ssstoyama/sticky"
+)
+
+type Repository interface{ Find(string) string }
+
+type MemoryRepository struct{}
+
+func (r *MemoryRepository) Find(string) string { return "" }
+
+type Service struct{ r Repository }
+
+type Unknown struct{}
+
+type Endpoint struct{}
+
+func NewService(r Repository) *Service { return &Service{r} }
+
+func register(c sticky.Container, tag string) {
+	sticky.Register(c,
+		sticky.Constructor(func() *MemoryRepository { return &MemoryRepository{} }, sticky.Implements[Repository]()),
+		sticky.Constructor(NewService, sticky.Tag("service")),
+		sticky.Constructor("dummy"), // want `sticky.Constructor: invalid constructor. must be factory function. got=string`
+		sticky.Constructor(func() *Service { return nil }, sticky.Implements[io.Reader]()), // want `sticky.Implements: \*a.Service does not implement io.Reader`
+		sticky.Param(100, "port"),
+		sticky.Supply[io.Writer](nil, sticky.Tag(tag)),
+		sticky.Supply("http://localhost", sticky.Qualified[Endpoint]()),
+	)
+}
+
+func resolve(c sticky.Container) {
+	sticky.Resolve[Repository](c)
+	sticky.Resolve[*Service](c, sticky.Tag("service"))
+	sticky.Resolve[*Service](c, sticky.Tag("other")) // want `sticky.Resolve: \*a.Service is not registered with tag "other"`
+	sticky.Resolve[*Unknown](c)                      // want `sticky.Resolve: \*a.Unknown is never registered`
+	sticky.Resolve[int](c, sticky.Tag("port"))
+	sticky.Resolve[io.Writer](c, sticky.Tag("any"))
+	sticky.Resolve[sticky.Named[string, Endpoint]](c)
+	sticky.Resolve[sticky.Named[*Unknown, Endpoint]](c)                    // want `sticky.Resolve: \*a.Unknown is never registered`
+	sticky.Decorate(c, func(u Unknown) (Unknown, error) { return u, nil }) // want `sticky.Decorate: a.Unknown is never registered`
+	sticky.Extract(c, func(r Repository, u *Unknown) {})                   // want `sticky.Extract: \*a.Unknown is never registered`
+	sticky.Extract(c, func(ctx context.Context, r Repository) {})
+	sticky.Extract(c, func(all sticky.All[*Unknown]) {})
+	sticky.Extract(c, func(s ...*Unknown) {})
+	sticky.Extract(c, func(f sticky.Future[Repository]) {})
+	sticky.Extract(c, func(f sticky.Future[*Unknown]) {}) // want `sticky.Extract: \*a.Unknown is never registered`
+}
@@ -0,0 +1,26 @@
+package generic
+
+import (
+	"reflect"
+
+	"github.com/ssstoyama/sticky"
+)
+
+type User struct{}
+
+type Repo[T any] struct{}
+
+func NewRepo[T any]() *Repo[T] { return &Repo[T]{} }
+
+func register(c sticky.Container) {
+	sticky.Register(c, sticky.Generic(func(t reflect.Type) any {
+		if t == reflect.TypeOf(&Repo[User]{}) {
+			return NewRepo[User]
+		}
+		return nil
+	}))
+}
+
+func resolve(c sticky.Container) {
+	sticky.Resolve[*Repo[User]](c)
+}
@@ -0,0 +1,53 @@
+// Package sticky is a stub of github.com/ssstoyama/sticky for the analyzer tests.
+package sticky
+
+import (
+	"io"
+	"reflect"
+)
+
+type Container interface{}
+
+type register interface{}
+
+type option interface{}
+
+func New(opts ...option) Container { return nil }
+
+func Parent(parent Container) option { return nil }
+
+func Register(c Container, rters ...register) error { return nil }
+
+func Constructor(fn any, opts ...option) register { return nil }
+
+func Param(value any, tag string) register { return nil }
+
+func Supply[T any](value T, opts ...option) register { return nil }
+
+func Bind[I, T any](opts ...option) register { return nil }
+
+func Generic(factory func(t reflect.Type) any, opts ...option) register { return nil }
+
+type Catalog struct{}
+
+func NewCatalog() *Catalog { return nil }
+
+func LoadManifest(c Container, cat *Catalog, r io.Reader) error { return nil }
+
+func Tag(tag string) option { return nil }
+
+func Implements[T any]() option { return nil }
+
+func Qualified[Q any]() option { return nil }
+
+type Named[T, Q any] struct{ Value T }
+
+type All[T any] map[string]T
+
+type Future[T any] struct{}
+
+func Resolve[T any](c Container, opts ...option) (ret T, err error) { return }
+
+func Decorate[T any](c Container, fn func(T) (T, error), opts ...option) error { return nil }
+
+func Extract(c Container, fn any) error { return nil }
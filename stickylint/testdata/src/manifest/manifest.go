@@ -0,0 +1,18 @@
+package manifest
+
+import (
+	"strings"
+
+	"github.com/ssstoyama/sticky"
+)
+
+type Repository struct{}
+
+func register(c sticky.Container) {
+	sticky.Register(c, sticky.Supply("http://localhost"))
+	sticky.LoadManifest(c, sticky.NewCatalog(), strings.NewReader("bindings: []"))
+}
+
+func resolve(c sticky.Container) {
+	sticky.Resolve[*Repository](c)
+}
@@ -0,0 +1,15 @@
+package parent
+
+import "github.com/ssstoyama/sticky"
+
+type Repository struct{}
+
+func register(base sticky.Container) sticky.Container {
+	c := sticky.New(sticky.Parent(base))
+	sticky.Register(c, sticky.Supply("http://localhost"))
+	return c
+}
+
+func resolve(c sticky.Container) {
+	sticky.Resolve[*Repository](c)
+}
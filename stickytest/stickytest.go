@@ -0,0 +1,101 @@
+// Package stickytest provides helpers for testing code wired with sticky.
+package stickytest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ssstoyama/sticky"
+)
+
+// New creates a container with the registrations and validates it.
+// it fails the test if a registration or the validation fails. the container is closed when the test finishes.
+func New(t testing.TB, rters ...sticky.Registration) sticky.Container {
+	t.Helper()
+	c := sticky.New()
+	t.Cleanup(func() {
+		if err := c.Close(context.Background()); err != nil {
+			t.Errorf("stickytest: close: %v", err)
+		}
+	})
+	if err := sticky.Register(c, rters...); err != nil {
+		t.Fatalf("stickytest: register: %v", err)
+	}
+	if err := sticky.Validate(c); err != nil {
+		t.Fatalf("stickytest: %v", err)
+	}
+	return c
+}
+
+// Override replaces the registrations of c with fakes until the test finishes.
+func Override(t testing.TB, c sticky.Container, rters ...sticky.Registration) {
+	t.Helper()
+	restore, err := sticky.Override(c, rters...)
+	if err != nil {
+		t.Fatalf("stickytest: override: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := restore(); err != nil {
+			t.Errorf("stickytest: restore: %v", err)
+		}
+	})
+}
+
+// MustResolve resolves T and fails the test if it can not be resolved.
+func MustResolve[T any](t testing.TB, c sticky.Container, opts ...sticky.ResolveOption) T {
+	t.Helper()
+	v, err := sticky.Resolve[T](c, opts...)
+	if err != nil {
+		t.Fatalf("stickytest: resolve: %v", err)
+	}
+	return v
+}
+
+// AssertResolvable asserts that T can be resolved.
+func AssertResolvable[T any](t testing.TB, c sticky.Container, opts ...sticky.ResolveOption) bool {
+	t.Helper()
+	if _, err := sticky.Resolve[T](c, opts...); err != nil {
+		t.Errorf("stickytest: %s is not resolvable: %v", typeOf[T](), err)
+		return false
+	}
+	return true
+}
+
+// AssertSingleton asserts that T is resolved once and then reused.
+func AssertSingleton[T any](t testing.TB, c sticky.Container, opts ...sticky.ResolveOption) bool {
+	t.Helper()
+	if _, err := sticky.Resolve[T](c, opts...); err != nil {
+		t.Errorf("stickytest: %s is not resolvable: %v", typeOf[T](), err)
+		return false
+	}
+	built, err := sticky.Built[T](c, opts...)
+	if err != nil {
+		t.Errorf("stickytest: %v", err)
+		return false
+	}
+	if !built {
+		t.Errorf("stickytest: %s is not a singleton", typeOf[T]())
+		return false
+	}
+	return true
+}
+
+// AssertNotBuilt asserts that T is registered and has not been built yet.
+func AssertNotBuilt[T any](t testing.TB, c sticky.Container, opts ...sticky.ResolveOption) bool {
+	t.Helper()
+	built, err := sticky.Built[T](c, opts...)
+	if err != nil {
+		t.Errorf("stickytest: %v", err)
+		return false
+	}
+	if built {
+		t.Errorf("stickytest: %s is already built", typeOf[T]())
+		return false
+	}
+	return true
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,90 @@
+package stickytest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ssstoyama/sticky"
+	"github.com/stretchr/testify/assert"
+)
+
+type repository interface{ Find() string }
+
+type memoryRepository struct{}
+
+func (r *memoryRepository) Find() string { return "memory" }
+
+type fakeRepository struct{}
+
+func (r *fakeRepository) Find() string { return "fake" }
+
+type service struct{ r repository }
+
+func registrations() []sticky.Registration {
+	return []sticky.Registration{
+		sticky.Constructor(func() *memoryRepository { return &memoryRepository{} }, sticky.Implements[repository]()),
+		sticky.Constructor(func(r repository) *service { return &service{r} }),
+		sticky.Constructor(func() string { return "transient" }, sticky.Cache(false)),
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	c := New(t, registrations()...)
+
+	AssertNotBuilt[*service](t, c)
+	AssertResolvable[*service](t, c)
+	AssertSingleton[*service](t, c)
+	assert.Equal(t, "memory", MustResolve[*service](t, c).r.Find())
+
+	t.Run("override", func(t *testing.T) {
+		Override(t, c, sticky.Constructor(func() *fakeRepository { return &fakeRepository{} }, sticky.Implements[repository]()))
+		assert.Equal(t, "fake", MustResolve[*service](t, c).r.Find())
+	})
+	assert.Equal(t, "memory", MustResolve[*service](t, c).r.Find())
+}
+
+// recorder is a testing.TB that records failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+func TestFailures(t *testing.T) {
+	c := New(t, registrations()...)
+
+	rec := &recorder{TB: t}
+	assert.False(t, AssertSingleton[string](rec, c))
+	assert.False(t, AssertResolvable[int](rec, c))
+	MustResolve[*service](rec, c)
+	assert.False(t, AssertNotBuilt[*service](rec, c))
+	assert.Len(t, rec.errors, 3)
+
+	rec = &recorder{TB: t}
+	New(rec, sticky.Constructor(func(n int) *service { return nil }))
+	assert.True(t, rec.fatal)
+
+	rec = &recorder{TB: t}
+	t.Run("restore", func(t *testing.T) {
+		rec.TB = t
+		Override(rec, c, sticky.Constructor(func() *failingCloser { return &failingCloser{} }))
+		MustResolve[*failingCloser](rec, c)
+	})
+	assert.Equal(t, []string{"stickytest: restore: close type=*github.com/ssstoyama/sticky/stickytest.failingCloser, tag='': close error"}, rec.errors)
+}
+
+type failingCloser struct{}
+
+func (*failingCloser) Close() error { return errors.New("close error") }
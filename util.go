@@ -19,44 +19,98 @@ func assertConstructor(v reflect.Value) error {
 	return nil
 }
 
-// assertNotCycle makes sure that the dependencies are not cycle.
-func assertNotCycle(c *container, deps []*dependency) error {
+// assertNotCycle makes sure that the dependencies registered with keys are not cycle. c.mu must be held.
+func assertNotCycle(c *container, keys []dKey, deps []*dependency) error {
+	pending := make(map[dKey]*dependency, len(keys))
+	for i, key := range keys {
+		pending[key] = deps[i]
+	}
 	for _, dep := range deps {
-		t := dep.value.Type()
-		if t.Kind() != reflect.Func {
+		if dep.isParam || dep.value.Kind() != reflect.Func {
 			continue
 		}
+		t := dep.value.Type()
 		var cerr cycleDependencyError
 		for i := 0; i < t.NumOut(); i++ {
 			cerr.deps = append(cerr.deps, t.Out(i))
 		}
-		if err := _assertNotCycle(c, dep, cerr); err != nil {
+		if err := _assertNotCycle(c, pending, dep, cerr, nil); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func _assertNotCycle(c *container, dep *dependency, cerr cycleDependencyError) error {
+// _assertNotCycle walks the dependencies of dep. path is the keys walked from the registered dependencies.
+func _assertNotCycle(c *container, pending map[dKey]*dependency, dep *dependency, cerr cycleDependencyError, path []dKey) error {
 	t := dep.value.Type()
 	for i := 0; i < t.NumIn(); i++ {
-		it := t.In(i)
-		for _, dt := range cerr.deps {
-			if it == dt {
-				cerr.deps = append(cerr.deps, it)
-				return &cerr
+		for _, key := range paramKeys(c, pending, dep, i) {
+			walked := cycleDependencyError{append(cerr.deps[:len(cerr.deps):len(cerr.deps)], key.t)}
+			if _, ok := pending[key]; ok {
+				return &walked
+			}
+			for _, k := range path {
+				if k == key {
+					return &walked
+				}
+			}
+			dep, ok := c.dependencies[key]
+			if !ok || dep.isParam {
+				continue
+			}
+			if err := _assertNotCycle(c, pending, dep, walked, append(path[:len(path):len(path)], key)); err != nil {
+				return err
 			}
 		}
-		cerr.deps = append(cerr.deps, it)
-		dep, ok := c.dependencies[dKey{t: it}]
-		if !ok {
-			continue
+	}
+	return nil
+}
+
+// paramKeys returns the keys that the i-th parameter of the constructor of dep is resolved with,
+// including the pending keys being registered. the parameters of Named and Future types are resolved with
+// the keys of their element types, and the ones of All and variadic types with all the bindings of them. c.mu must be held.
+func paramKeys(c *container, pending map[dKey]*dependency, dep *dependency, i int) []dKey {
+	t := dep.value.Type()
+	it := t.In(i)
+	variadic := t.IsVariadic() && i == t.NumIn()-1
+	var elemT reflect.Type
+	switch {
+	case variadic:
+		elemT = it.Elem()
+	case it.Implements(futureParamType):
+		return []dKey{{t: reflect.Zero(it).Interface().(futureParam).futureElemType()}}
+	case it.Implements(allParamType):
+		elemT = reflect.Zero(it).Interface().(allParam).elemType()
+	default:
+		return []dKey{argKey(it)}
+	}
+	var keys []dKey
+	for _, deps := range []map[dKey]*dependency{c.dependencies, pending} {
+		for key, d := range deps {
+			if variadic && dep.fromGroup != "" {
+				if d.group == dep.fromGroup && key.t.AssignableTo(elemT) {
+					keys = append(keys, key)
+				}
+				continue
+			}
+			if key.t == elemT {
+				keys = append(keys, key)
+			}
 		}
-		if err := _assertNotCycle(c, dep, cerr); err != nil {
-			return err
+	}
+	return keys
+}
+
+// without returns a copy of s without v.
+func without[T comparable](s []T, v T) []T {
+	ret := make([]T, 0, len(s))
+	for _, e := range s {
+		if e != v {
+			ret = append(ret, e)
 		}
 	}
-	return nil
+	return ret
 }
 
 // get constructor from context.
@@ -64,11 +118,15 @@ func getContainer(ctx stickyContext) (*container, error) {
 	if c, ok := ctx.(*container); ok {
 		return c, nil
 	}
-	c := ctx.Value(defaultKey)
-	if c == nil {
+	v := ctx.Value(defaultKey)
+	if v == nil {
 		return nil, errors.New("not found container in context")
 	}
-	return c.(*container), nil
+	c, ok := v.(*container)
+	if !ok {
+		return nil, fmt.Errorf("invalid container in context. got=%T", v)
+	}
+	return c, nil
 }
 
 // indirectType returns the type that t points to
@@ -86,6 +144,9 @@ func makeType[T any]() reflect.Type {
 
 func pathString(t reflect.Type) string {
 	_t := indirectType(t)
+	if _t.PkgPath() == "" {
+		return t.String()
+	}
 	path := fmt.Sprintf("%s.%s", _t.PkgPath(), _t.Name())
 	if t.Kind() == reflect.Ptr {
 		return "*" + path
@@ -25,6 +25,11 @@ func TestPath(t *testing.T) {
 			input:  &A{},
 			output: "*github.com/ssstoyama/sticky.A",
 		},
+		{
+			name:   "unnamed type",
+			input:  []int{},
+			output: "[]int",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {